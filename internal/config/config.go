@@ -2,10 +2,13 @@ package config
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,101 +16,428 @@ import (
 )
 
 type Tab struct {
-	Title           string   `toml:"title"`
-	Cmd             []string `toml:"cmd"`
-	Disabled        bool     `toml:"-"`
-	DisabledMsg     string   `toml:"-"`
-	RefreshInterval duration `toml:"refresh_interval"`
+	Title string   `toml:"title"`
+	Cmd   []string `toml:"cmd"`
+	// Cmds lists alternative commands the user can cycle through. When set
+	// without cmd, the first one is the default.
+	Cmds            [][]string `toml:"cmds"`
+	Builtin         string     `toml:"builtin"`
+	Disabled        bool       `toml:"-"`
+	DisabledMsg     string     `toml:"-"`
+	RefreshInterval duration   `toml:"refresh_interval"`
+	InstallHint     string     `toml:"install_hint"`
+	OS              []string   `toml:"os"`
+	HeaderLines     int        `toml:"header_lines"`
+	Filter          string     `toml:"filter"`
+	Transform       []string   `toml:"transform"`
+	Accent          string     `toml:"accent"`
+	// Static tabs run once and are never refreshed automatically.
+	Static bool `toml:"static"`
+	// CacheTTL reuses the tab's last output for this long instead of
+	// re-running the command on every tick.
+	CacheTTL duration `toml:"cache_ttl"`
+	// MaxOutputBytes caps how much of the command's output is kept; 0
+	// means the built-in limit.
+	MaxOutputBytes int `toml:"max_output_bytes"`
+	// Highlight lists words always drawn in the warning colors, the
+	// first in red and the rest in yellow.
+	Highlight []string `toml:"highlight"`
+	// Pinned tabs stay visible at the edges of the tab bar when it
+	// overflows.
+	Pinned bool `toml:"pinned"`
+	// Mounts are the filesystems the overview builtin draws a disk gauge
+	// for; empty means "/".
+	Mounts []string `toml:"mounts"`
+	// FilterRe is Filter compiled by validateTab.
+	FilterRe *regexp.Regexp `toml:"-"`
+	// TTYWarning, set by validateTab, notes that the command likely needs
+	// a terminal.
+	TTYWarning string `toml:"-"`
+}
+
+// Derived is a custom metric computed from the sampled ones, e.g. name
+// "avg" with expr "(cpu + mem) / 2", and plotted as an extra block.
+type Derived struct {
+	Name string `toml:"name"`
+	Expr string `toml:"expr"`
 }
 
 type Config struct {
-	Tabs                  []Tab    `toml:"tab"`
-	GlobalRefreshInterval duration `toml:"global_refresh_interval"`
+	Tabs []Tab `toml:"tab"`
+	// ExtraTabs are appended after the tabs, or after the defaults when
+	// the config defines no tabs of its own.
+	ExtraTabs []Tab `toml:"extra_tab"`
+	// Derived lists custom metrics for the metrics row.
+	Derived               []Derived `toml:"derived"`
+	GlobalRefreshInterval duration  `toml:"global_refresh_interval"`
+	NetUnit               string    `toml:"net_unit"`
+	Prefetch              bool      `toml:"prefetch"`
+	NormalizeWhitespace   bool      `toml:"normalize_whitespace"`
+	MemDetail             bool      `toml:"mem_detail"`
+	// DisabledDefaults drops default tabs by title, or by the title's
+	// first word, when the config defines no tabs of its own.
+	DisabledDefaults []string `toml:"disabled_defaults"`
+	// UptimeDetail shows the full uptime and boot time in the info row.
+	UptimeDetail bool `toml:"uptime_detail"`
+	// QuietErrors hides a failed run until this many runs in a row have
+	// failed; 0 or 1 shows every failure.
+	QuietErrors int    `toml:"quiet_errors"`
+	Layout      string `toml:"layout"`
+	// MetricsPriority orders the metrics row blocks (cpu, mem, load, net)
+	// by importance; the last ones are hidden first on narrow terminals.
+	MetricsPriority []string `toml:"metrics_priority"`
+	// PeakHold marks the highest value in each sparkline.
+	PeakHold bool `toml:"peak_hold"`
+	// Dense drops the padding inside the content box, and HideBorder its
+	// border, so wide tables fit.
+	Dense      bool `toml:"dense"`
+	HideBorder bool `toml:"hide_border"`
+	// ScaleLabel appends the top of the scale to the auto-scaled LOAD and
+	// NET sparklines, e.g. "(max 2.0MiB/s)".
+	ScaleLabel bool `toml:"scale_label"`
+	// HistoryWindow labels the metrics row with the time the sparklines
+	// span, e.g. "last 2m30s".
+	HistoryWindow bool `toml:"history_window"`
+	// MissingSamples is what the sparklines record when a metric fails to
+	// sample: "skip" (pause), "carry" (repeat the last value) or "gap".
+	MissingSamples string `toml:"missing_samples"`
+	// StripColor removes all ANSI styling from command output.
+	StripColor bool `toml:"strip_color"`
+	// NetInterfaces, when set, shows these interfaces' rx/tx rates side by
+	// side in the system row.
+	NetInterfaces []string `toml:"net_interfaces"`
+	// NetLoopbackFallback counts loopback traffic in NET when it is the
+	// only interface, e.g. inside a network namespace.
+	NetLoopbackFallback bool `toml:"net_loopback_fallback"`
+	// Separator joins the blocks of the metrics and system rows; empty
+	// means three spaces.
+	Separator string `toml:"separator"`
+	// TimeFormat is the Go time layout of the status line timestamp;
+	// empty means "15:04:05".
+	TimeFormat string `toml:"time_format"`
+	// LoadingPlaceholder shows "Loading..." while a tab runs; nil means on.
+	LoadingPlaceholder *bool `toml:"loading_placeholder"`
+	// SmoothingAlpha weights each new metric sample against the previous
+	// value (1 = no smoothing); nil means 1.
+	SmoothingAlpha *float64 `toml:"smoothing"`
+	// AlertCPU and AlertMem are usage percentages at which an alert fires;
+	// zero disables them.
+	AlertCPU float64 `toml:"alert_cpu"`
+	AlertMem float64 `toml:"alert_mem"`
+	// OnAlertFocus is the title of the tab to switch to when an alert fires.
+	OnAlertFocus string `toml:"on_alert_focus"`
+	// TabAlign places the tab bar within the header: left, center or right.
+	TabAlign string `toml:"tab_align"`
+	// CenterContent centers output shorter than the content box, like
+	// uptime, instead of leaving it in the top-left corner.
+	CenterContent bool `toml:"center_content"`
+	// SkipDisabled makes next/previous tab navigation jump over disabled
+	// tabs.
+	SkipDisabled bool `toml:"skip_disabled"`
+	// ConfirmQuit asks for a second q/Esc press before quitting.
+	ConfirmQuit bool `toml:"confirm_quit"`
+}
+
+const (
+	// DefaultSmoothing leaves samples unsmoothed.
+	DefaultSmoothing = 1.0
+	// MinSmoothing is the smallest alpha allowed; at 0 every new sample
+	// would be ignored and the metrics would freeze.
+	MinSmoothing = 0.1
+)
+
+// Smoothing returns the EWMA alpha for metric samples, clamped to
+// [MinSmoothing,1].
+func (c Config) Smoothing() float64 {
+	if c.SmoothingAlpha == nil {
+		return DefaultSmoothing
+	}
+	return min(max(*c.SmoothingAlpha, MinSmoothing), 1)
+}
+
+// ShowLoadingPlaceholder reports whether tab switches should clear the
+// content to "Loading..." until output arrives.
+func (c Config) ShowLoadingPlaceholder() bool {
+	return c.LoadingPlaceholder == nil || *c.LoadingPlaceholder
+}
+
+// DefaultSeparator joins the metrics and system row blocks unless
+// separator is set.
+const DefaultSeparator = "   "
+
+// ColumnSeparator is the separator between metrics and system row blocks.
+func (c Config) ColumnSeparator() string {
+	if c.Separator == "" {
+		return DefaultSeparator
+	}
+	return c.Separator
+}
+
+// DefaultTimeFormat is the status line timestamp layout unless
+// time_format is set.
+const DefaultTimeFormat = "15:04:05"
+
+// StatusTimeFormat is the layout for the status line timestamp.
+func (c Config) StatusTimeFormat() string {
+	if c.TimeFormat == "" {
+		return DefaultTimeFormat
+	}
+	return c.TimeFormat
+}
+
+// Built-in tabs render without running an external command.
+const (
+	// BuiltinAbout renders a host summary.
+	BuiltinAbout = "about"
+	// BuiltinOverview renders a dashboard of every sampled metric.
+	BuiltinOverview = "overview"
+)
+
+// Supported net_unit values.
+const (
+	NetUnitBytes = "bytes"
+	NetUnitBits  = "bits"
+)
+
+func normalizeNetUnit(unit string) string {
+	if strings.EqualFold(strings.TrimSpace(unit), NetUnitBits) {
+		return NetUnitBits
+	}
+	return NetUnitBytes
+}
+
+// Supported layout values: where the tabs, metrics and footer sit relative
+// to the content box.
+const (
+	LayoutTop    = "top"
+	LayoutBottom = "bottom"
+)
+
+func normalizeLayout(layout string) string {
+	if strings.EqualFold(strings.TrimSpace(layout), LayoutBottom) {
+		return LayoutBottom
+	}
+	return LayoutTop
+}
+
+// Supported missing_samples values: what a metric's history records when a
+// sample of it fails.
+const (
+	MissingSkip  = "skip"
+	MissingCarry = "carry"
+	MissingGap   = "gap"
+)
+
+func normalizeMissingSamples(mode string) string {
+	switch mode = strings.ToLower(strings.TrimSpace(mode)); mode {
+	case MissingCarry, MissingGap:
+		return mode
+	}
+	return MissingSkip
+}
+
+// Supported tab_align values.
+const (
+	TabAlignLeft   = "left"
+	TabAlignCenter = "center"
+	TabAlignRight  = "right"
+)
+
+func normalizeTabAlign(align string) string {
+	switch a := strings.ToLower(strings.TrimSpace(align)); a {
+	case TabAlignCenter, TabAlignRight:
+		return a
+	}
+	return TabAlignLeft
 }
 
 // Custom duration type for TOML parsing
 type duration struct {
 	time.Duration
+	// set records that the value came from the file, so an explicit "0"
+	// can be told apart from an omitted interval.
+	set bool
 }
 
 func (d *duration) UnmarshalText(text []byte) error {
 	var err error
 	d.Duration, err = time.ParseDuration(string(text))
+	d.set = err == nil
 	return err
 }
 
-func Load() (Config, []Tab) {
-	if cfg, ok := loadFromConfig(); ok {
-		validated := make([]Tab, 0, len(cfg.Tabs))
-		for _, t := range cfg.Tabs {
-			validated = append(validated, validateTab(t))
+// defaultRefreshInterval is global_refresh_interval when it is unset.
+const defaultRefreshInterval = 5 * time.Second
+
+// LoadOptions are command-line choices that shape the loaded tabs.
+type LoadOptions struct {
+	// AdHocCmd, set from --cmd, replaces the config with a single tab
+	// running this command.
+	AdHocCmd []string
+	// DisableDefaults names default tabs to drop on top of
+	// disabled_defaults, e.g. from the --disable flag.
+	DisableDefaults []string
+}
+
+// Load reads the config file, falling back to the default tabs, and
+// applies opts.
+func Load(opts LoadOptions) (Config, []Tab) {
+	var cfg Config
+	if len(opts.AdHocCmd) == 0 {
+		cfg, _ = loadFromConfig()
+	} else {
+		log.Printf("config: running --cmd %q, ignoring config files", opts.AdHocCmd)
+	}
+
+	if cfg.GlobalRefreshInterval.Duration <= 0 {
+		cfg.GlobalRefreshInterval.Duration = defaultRefreshInterval
+	}
+	cfg.NetUnit = normalizeNetUnit(cfg.NetUnit)
+	cfg.Layout = normalizeLayout(cfg.Layout)
+	cfg.TabAlign = normalizeTabAlign(cfg.TabAlign)
+	cfg.MissingSamples = normalizeMissingSamples(cfg.MissingSamples)
+
+	validated := make([]Tab, 0, len(cfg.Tabs)+len(cfg.ExtraTabs))
+	if len(opts.AdHocCmd) > 0 {
+		validated = append(validated, validateTab(adHocTab(opts.AdHocCmd)))
+	}
+	for _, t := range cfg.Tabs {
+		validated = append(validated, validateTab(t))
+	}
+	if len(validated) == 0 {
+		disabled := append(append([]string(nil), cfg.DisabledDefaults...), opts.DisableDefaults...)
+		validated = withoutDefaults(buildDefaultTabs(cfg.GlobalRefreshInterval), disabled)
+	}
+	for _, t := range cfg.ExtraTabs {
+		validated = append(validated, validateTab(t))
+	}
+
+	// Apply global refresh if tab refresh is missing. refresh_interval = "0"
+	// marks the tab static; it still ticks metrics at the global rate.
+	for i := range validated {
+		if validated[i].RefreshInterval.set && validated[i].RefreshInterval.Duration == 0 {
+			validated[i].Static = true
 		}
-		if len(validated) > 0 {
-			// Apply global refresh if tab refresh is missing
-			if cfg.GlobalRefreshInterval.Duration <= 0 {
-				cfg.GlobalRefreshInterval.Duration = 5 * time.Second
-			}
-			for i := range validated {
-				if validated[i].RefreshInterval.Duration <= 0 {
-					validated[i].RefreshInterval = cfg.GlobalRefreshInterval
-				}
-			}
-			return cfg, validated
+		if validated[i].RefreshInterval.Duration <= 0 {
+			validated[i].RefreshInterval = cfg.GlobalRefreshInterval
 		}
 	}
-	return Config{GlobalRefreshInterval: duration{5 * time.Second}}, buildDefaultTabs()
+	return cfg, validated
 }
 
+// loadFromConfig decodes the first readable config file. Settings from that
+// file apply even when it defines no usable tabs; the defaults fill in.
 func loadFromConfig() (Config, bool) {
 	paths := configPaths()
 	for _, path := range paths {
 		data, err := os.ReadFile(path)
 		if err != nil {
+			log.Printf("config: skip %s: %v", path, err)
 			continue
 		}
 		var cfg Config
 		if _, err := toml.Decode(string(data), &cfg); err != nil {
-			// In a real app we might want to log this error
-			continue
-		}
-		if len(cfg.Tabs) == 0 {
+			log.Printf("config: parse %s: %v", path, err)
 			continue
 		}
 
-		// Filter invalid tabs
-		validTabs := make([]Tab, 0, len(cfg.Tabs))
-		for _, t := range cfg.Tabs {
-			if t.Title != "" && len(t.Cmd) > 0 {
-				validTabs = append(validTabs, t)
-			}
-		}
+		cfg.Tabs = usableTabs(cfg.Tabs)
+		cfg.ExtraTabs = usableTabs(cfg.ExtraTabs)
 
-		if len(validTabs) > 0 {
-			cfg.Tabs = validTabs
-			return cfg, true
+		if len(cfg.Tabs) > 0 {
+			log.Printf("config: loaded %d tabs from %s", len(cfg.Tabs), path)
+		} else {
+			log.Printf("config: %s has no usable tabs, using defaults", path)
 		}
+		return cfg, true
 	}
+	log.Printf("config: no config file found, using defaults")
 	return Config{}, false
 }
 
+// usableTabs drops tabs without a title or anything to run, and tabs
+// limited to other platforms.
+func usableTabs(tabs []Tab) []Tab {
+	valid := make([]Tab, 0, len(tabs))
+	for _, t := range tabs {
+		if t.Title != "" && (len(t.Cmd) > 0 || len(t.Cmds) > 0 || t.Builtin != "") && supportsOS(t) {
+			valid = append(valid, t)
+		}
+	}
+	return valid
+}
+
+// configPaths lists candidate config files in search order. The XDG
+// location is probed on every platform before os.UserConfigDir, which on
+// macOS is ~/Library/Application Support rather than ~/.config.
 func configPaths() []string {
 	var paths []string
+	add := func(path string) {
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
 	if env := strings.TrimSpace(os.Getenv("PERFDECK_CONFIG")); env != "" {
-		paths = append(paths, env)
+		add(ExpandHome(env))
+	}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(xdg) {
+		add(filepath.Join(xdg, "perfdeck", "config.toml"))
+	} else if home, err := os.UserHomeDir(); err == nil {
+		add(filepath.Join(home, ".config", "perfdeck", "config.toml"))
 	}
 	if cfgDir, err := os.UserConfigDir(); err == nil {
-		paths = append(paths, filepath.Join(cfgDir, "perfdeck", "config.toml"))
+		add(filepath.Join(cfgDir, "perfdeck", "config.toml"))
 	}
-	paths = append(paths, "perfdeck.toml")
+	add("perfdeck.toml")
 	return paths
 }
 
+var hexColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
 func validateTab(t Tab) Tab {
+	if t.Accent != "" && !hexColorRe.MatchString(t.Accent) {
+		t.Disabled = true
+		t.DisabledMsg = fmt.Sprintf("Invalid accent %q. Use a hex color like \"#f87171\".", t.Accent)
+		return t
+	}
+
+	if t.Builtin != "" {
+		if t.Builtin != BuiltinAbout && t.Builtin != BuiltinOverview {
+			t.Disabled = true
+			t.DisabledMsg = fmt.Sprintf("Unknown builtin %q.", t.Builtin)
+		}
+		return t
+	}
+
+	t.Cmds = nonEmptyCmds(t.Cmds)
+	switch {
+	case len(t.Cmds) == 0:
+	case len(t.Cmd) == 0:
+		t.Cmd = t.Cmds[0]
+	case !slices.Equal(t.Cmd, t.Cmds[0]):
+		// An explicit cmd is the default variant.
+		t.Cmds = append([][]string{t.Cmd}, t.Cmds...)
+	}
 	if len(t.Cmd) == 0 {
 		t.Disabled = true
 		t.DisabledMsg = "No command configured for this tab."
 		return t
 	}
+	t.Cmd = withBatchFlags(t.Cmd)
+	for i := range t.Cmds {
+		t.Cmds[i] = withBatchFlags(t.Cmds[i])
+	}
+	t.TTYWarning = ttyWarning(t.Cmd)
+
+	if t.Filter != "" {
+		re, err := regexp.Compile(t.Filter)
+		if err != nil {
+			t.Disabled = true
+			t.DisabledMsg = fmt.Sprintf("Invalid filter %q: %v", t.Filter, err)
+			return t
+		}
+		t.FilterRe = re
+	}
 
 	// If fetch is already handled by a safe echo command, leave it enabled.
 	if t.Cmd[0] == "echo" {
@@ -119,7 +449,11 @@ func validateTab(t Tab) Tab {
 	}
 
 	t.Disabled = true
-	t.DisabledMsg = missingHint(t.Cmd[0], t.Title)
+	if t.InstallHint != "" {
+		t.DisabledMsg = t.InstallHint
+	} else {
+		t.DisabledMsg = missingHint(t.Cmd[0], t.Title)
+	}
 	return t
 }
 
@@ -143,26 +477,51 @@ func missingHint(cmd, title string) string {
 
 const osDarwin = "darwin"
 
-func buildDefaultTabs() []Tab {
+// goos is the platform tabs are filtered against; tests override it.
+var goos = runtime.GOOS
+
+// supportsOS reports whether the tab applies to the current platform.
+// An empty os list means the tab runs everywhere.
+func supportsOS(t Tab) bool {
+	if len(t.OS) == 0 {
+		return true
+	}
+	for _, name := range t.OS {
+		if strings.EqualFold(strings.TrimSpace(name), goos) {
+			return true
+		}
+	}
+	return false
+}
+
+func buildDefaultTabs(defaultInterval duration) []Tab {
+	tabs := defaultTabs(defaultInterval)
+	for i := range tabs {
+		tabs[i] = validateTab(tabs[i])
+	}
+	return tabs
+}
+
+// defaultTabs lists the tabs shown when the config defines none, with the
+// platform's variants of free and top, before validation.
+func defaultTabs(defaultInterval duration) []Tab {
 	freeCmd := []string{"free", "-m"}
 	freeTitle := "free -m"
-	if runtime.GOOS == osDarwin {
+	if goos == osDarwin {
 		freeCmd = []string{"vm_stat"}
 		freeTitle = "vm_stat (free)"
 	}
 
 	topCmd := []string{"top", "-b", "-n", "1"}
 	topTitle := "top -b -n 1"
-	if runtime.GOOS == "darwin" {
+	if goos == osDarwin {
 		topCmd = []string{"top", "-l", "1"}
 		topTitle = "top -l 1"
 	}
 
 	fetchTitle, fetchCmd := detectFetchCmd()
 
-	defaultInterval := duration{5 * time.Second}
-
-	tabs := []Tab{
+	return []Tab{
 		{Title: "uptime", Cmd: []string{"uptime"}, RefreshInterval: defaultInterval},
 		{Title: "vmstat", Cmd: []string{"vmstat"}, RefreshInterval: defaultInterval},
 		{Title: "mpstat -P ALL", Cmd: []string{"mpstat", "-P", "ALL"}, RefreshInterval: defaultInterval},
@@ -172,14 +531,53 @@ func buildDefaultTabs() []Tab {
 		{Title: "sar -n DEV", Cmd: []string{"sar", "-n", "DEV"}, RefreshInterval: defaultInterval},
 		{Title: "sar -n TCP,ETCP", Cmd: []string{"sar", "-n", "TCP,ETCP"}, RefreshInterval: defaultInterval},
 		{Title: topTitle, Cmd: topCmd, RefreshInterval: defaultInterval},
-		{Title: fetchTitle, Cmd: fetchCmd, RefreshInterval: defaultInterval},
+		{Title: fetchTitle, Cmd: fetchCmd, RefreshInterval: defaultInterval, Static: true},
+		{Title: "overview", Builtin: BuiltinOverview, RefreshInterval: defaultInterval},
+		{Title: "about", Builtin: BuiltinAbout, RefreshInterval: defaultInterval},
 	}
+}
 
-	for i := range tabs {
-		tabs[i] = validateTab(tabs[i])
+// withoutDefaults drops the default tabs named in disabled. A name matches
+// a tab's full title or its first word, ignoring case, so "pidstat" drops
+// "pidstat -p ALL". If nothing would be left the tabs are kept as they are.
+func withoutDefaults(tabs []Tab, disabled []string) []Tab {
+	if len(disabled) == 0 {
+		return tabs
 	}
+	drop := func(title string) bool {
+		title = strings.ToLower(title)
+		first, _, _ := strings.Cut(title, " ")
+		for _, name := range disabled {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name != "" && (name == title || name == first) {
+				return true
+			}
+		}
+		return false
+	}
+	kept := make([]Tab, 0, len(tabs))
+	for _, t := range tabs {
+		if drop(t.Title) {
+			log.Printf("config: default tab %q disabled", t.Title)
+			continue
+		}
+		kept = append(kept, t)
+	}
+	if len(kept) == 0 {
+		log.Printf("config: every default tab is disabled, keeping them all")
+		return tabs
+	}
+	return kept
+}
 
-	return tabs
+func nonEmptyCmds(cmds [][]string) [][]string {
+	var out [][]string
+	for _, c := range cmds {
+		if len(c) > 0 {
+			out = append(out, c)
+		}
+	}
+	return out
 }
 
 func detectFetchCmd() (string, []string) {
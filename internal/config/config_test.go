@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -55,7 +57,7 @@ cmd = ["vmstat"]
 	}
 
 	t.Setenv("PERFDECK_CONFIG", path)
-	_, tabs := Load() // Load now returns (Config, []Tab)
+	_, tabs := Load(LoadOptions{}) // Load now returns (Config, []Tab)
 
 	if len(tabs) != 1 {
 		t.Fatalf("expected 1 tab")
@@ -65,3 +67,531 @@ cmd = ["vmstat"]
 		t.Errorf("expected inherited 2s refresh, got %v", tabs[0].RefreshInterval.Duration)
 	}
 }
+
+func TestValidateTabUsesInstallHint(t *testing.T) {
+	tab := validateTab(Tab{
+		Title:       "k9s",
+		Cmd:         []string{"perfdeck-missing-binary-k9s"},
+		InstallHint: "Install k9s from https://k9scli.io.",
+	})
+	if !tab.Disabled {
+		t.Fatalf("expected tab to be disabled")
+	}
+	if tab.DisabledMsg != "Install k9s from https://k9scli.io." {
+		t.Errorf("expected configured hint, got %q", tab.DisabledMsg)
+	}
+
+	tab = validateTab(Tab{Title: "k9s", Cmd: []string{"perfdeck-missing-binary-k9s"}})
+	if tab.DisabledMsg != "Missing perfdeck-missing-binary-k9s. Install the command to enable this tab." {
+		t.Errorf("expected generic hint, got %q", tab.DisabledMsg)
+	}
+}
+
+func TestLoadFiltersTabsByOS(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "perfdeck.toml")
+	err := os.WriteFile(path, []byte(`
+[[tab]]
+title = "free"
+cmd = ["echo", "free"]
+os = ["linux"]
+
+[[tab]]
+title = "vm_stat"
+cmd = ["echo", "vm_stat"]
+os = ["darwin"]
+
+[[tab]]
+title = "uptime"
+cmd = ["echo", "uptime"]
+`), 0o644)
+	if err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	orig := goos
+	t.Cleanup(func() { goos = orig })
+	goos = "darwin"
+
+	_, tabs := Load(LoadOptions{})
+	if len(tabs) != 2 {
+		t.Fatalf("expected 2 tabs, got %d", len(tabs))
+	}
+	if tabs[0].Title != "vm_stat" || tabs[1].Title != "uptime" {
+		t.Errorf("unexpected tabs: %q, %q", tabs[0].Title, tabs[1].Title)
+	}
+}
+
+func TestLoadNetUnit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "perfdeck.toml")
+	if err := os.WriteFile(path, []byte(`net_unit = "Bits"`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	cfg, tabs := Load(LoadOptions{})
+	if cfg.NetUnit != NetUnitBits {
+		t.Errorf("expected net_unit %q, got %q", NetUnitBits, cfg.NetUnit)
+	}
+	if len(tabs) == 0 {
+		t.Errorf("expected default tabs when config defines none")
+	}
+
+	if err := os.WriteFile(path, []byte(`net_unit = "furlongs"`), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	cfg, _ = Load(LoadOptions{})
+	if cfg.NetUnit != NetUnitBytes {
+		t.Errorf("expected fallback to %q, got %q", NetUnitBytes, cfg.NetUnit)
+	}
+}
+
+func TestLoadLayout(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "perfdeck.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`layout = "bottom"`, LayoutBottom},
+		{`layout = "Bottom"`, LayoutBottom},
+		{`layout = "sideways"`, LayoutTop},
+		{``, LayoutTop},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(path, []byte(tt.input), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if cfg, _ := Load(LoadOptions{}); cfg.Layout != tt.want {
+			t.Errorf("Load(%q) layout = %q, want %q", tt.input, cfg.Layout, tt.want)
+		}
+	}
+}
+
+func TestLoadTabAlign(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perfdeck.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`tab_align = "center"`, TabAlignCenter},
+		{`tab_align = "Right"`, TabAlignRight},
+		{`tab_align = "middle"`, TabAlignLeft},
+		{``, TabAlignLeft},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(path, []byte(tt.input), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if cfg, _ := Load(LoadOptions{}); cfg.TabAlign != tt.want {
+			t.Errorf("Load(%q) tab_align = %q, want %q", tt.input, cfg.TabAlign, tt.want)
+		}
+	}
+}
+
+func TestLoadMissingSamples(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perfdeck.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`missing_samples = "Carry"`, MissingCarry},
+		{`missing_samples = "gap"`, MissingGap},
+		{`missing_samples = "zero"`, MissingSkip},
+		{``, MissingSkip},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(path, []byte(tt.input), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if cfg, _ := Load(LoadOptions{}); cfg.MissingSamples != tt.want {
+			t.Errorf("Load(%q) missing_samples = %q, want %q", tt.input, cfg.MissingSamples, tt.want)
+		}
+	}
+}
+
+func TestLoadSeparator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perfdeck.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`separator = " │ "`, " │ "},
+		{`separator = ""`, DefaultSeparator},
+		{``, DefaultSeparator},
+	}
+	for _, tt := range tests {
+		if err := os.WriteFile(path, []byte(tt.input), 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		if cfg, _ := Load(LoadOptions{}); cfg.ColumnSeparator() != tt.want {
+			t.Errorf("Load(%q) separator = %q, want %q", tt.input, cfg.ColumnSeparator(), tt.want)
+		}
+	}
+}
+
+func TestWithoutDefaults(t *testing.T) {
+	tabs := buildDefaultTabs(duration{Duration: time.Second})
+	titles := func(tabs []Tab) map[string]bool {
+		out := make(map[string]bool)
+		for _, t := range tabs {
+			out[t.Title] = true
+		}
+		return out
+	}
+
+	got := titles(withoutDefaults(tabs, []string{"uptime", "PIDSTAT", " iostat "}))
+	for _, gone := range []string{"uptime", "pidstat -p ALL", "iostat"} {
+		if got[gone] {
+			t.Errorf("%q should be disabled", gone)
+		}
+	}
+	for _, kept := range []string{"vmstat", "mpstat -P ALL", "about"} {
+		if !got[kept] {
+			t.Errorf("%q should be kept", kept)
+		}
+	}
+	// "sar" drops both sar tabs; a full title drops just one.
+	if got := titles(withoutDefaults(tabs, []string{"sar"})); got["sar -n DEV"] || got["sar -n TCP,ETCP"] {
+		t.Error("sar should drop every sar tab")
+	}
+	if got := titles(withoutDefaults(tabs, []string{"sar -n DEV"})); got["sar -n DEV"] || !got["sar -n TCP,ETCP"] {
+		t.Error("a full title should drop only that tab")
+	}
+
+	if len(withoutDefaults(tabs, nil)) != len(tabs) {
+		t.Error("no names should keep every tab")
+	}
+	var all []string
+	for _, tab := range tabs {
+		all = append(all, tab.Title)
+	}
+	if len(withoutDefaults(tabs, all)) != len(tabs) {
+		t.Error("disabling every tab should keep them all")
+	}
+}
+
+func TestLoadDisabledDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perfdeck.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+	if err := os.WriteFile(path, []byte(`disabled_defaults = ["uptime"]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, tabs := Load(LoadOptions{DisableDefaults: []string{"vmstat"}})
+	for _, tab := range tabs {
+		if tab.Title == "uptime" || tab.Title == "vmstat" {
+			t.Errorf("%q should be disabled", tab.Title)
+		}
+	}
+
+	// Custom tabs are never filtered.
+	if err := os.WriteFile(path, []byte("disabled_defaults = [\"uptime\"]\n[[tab]]\ntitle = \"uptime\"\ncmd = [\"uptime\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, tabs := Load(LoadOptions{}); len(tabs) != 1 || tabs[0].Title != "uptime" {
+		t.Errorf("custom tabs = %+v, want the uptime tab kept", tabs)
+	}
+}
+
+func TestLoadExtraTabs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perfdeck.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+	if err := os.WriteFile(path, []byte("[[extra_tab]]\ntitle = \"gpu\"\ncmd = [\"nvidia-smi\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defaults := buildDefaultTabs(duration{Duration: 5 * time.Second})
+
+	cfg, tabs := Load(LoadOptions{})
+	if len(tabs) != len(defaults)+1 {
+		t.Fatalf("got %d tabs, want the %d defaults plus one", len(tabs), len(defaults))
+	}
+	last := tabs[len(tabs)-1]
+	if last.Title != "gpu" || last.RefreshInterval.Duration != cfg.GlobalRefreshInterval.Duration {
+		t.Errorf("extra tab = %+v, want gpu at the global refresh", last)
+	}
+}
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"top -b -n 1", []string{"top", "-b", "-n", "1"}},
+		{`grep -E "a|b c" '/var/log/x y'`, []string{"grep", "-E", "a|b c", "/var/log/x y"}},
+		{`echo it\'s ""`, []string{"echo", "it's", ""}},
+		{`sh -c 'echo "$HOME"'`, []string{"sh", "-c", `echo "$HOME"`}},
+		{"  spaced\tout  ", []string{"spaced", "out"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitCommand(tt.in)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("SplitCommand(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "   ", `echo "open`, "echo 'open", `echo \`} {
+		if _, err := SplitCommand(bad); err == nil {
+			t.Errorf("SplitCommand(%q) should fail", bad)
+		}
+	}
+}
+
+func TestLoadAdHocCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perfdeck.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+	if err := os.WriteFile(path, []byte("[[tab]]\ntitle = \"ignored\"\ncmd = [\"uptime\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := []string{"echo", "hello world"}
+	cfg, tabs := Load(LoadOptions{AdHocCmd: cmd})
+	if len(tabs) != 1 {
+		t.Fatalf("got %d tabs, want only the ad-hoc tab", len(tabs))
+	}
+	tab := tabs[0]
+	if tab.Title != "echo hello world" || !slices.Equal(tab.Cmd, cmd) || tab.Disabled {
+		t.Errorf("ad-hoc tab = %+v", tab)
+	}
+	if tab.RefreshInterval.Duration != cfg.GlobalRefreshInterval.Duration {
+		t.Errorf("refresh = %s, want the global default", tab.RefreshInterval.Duration)
+	}
+}
+
+func TestWithBatchFlags(t *testing.T) {
+	defer func(orig string) { goos = orig }(goos)
+	tests := []struct {
+		goos string
+		cmd  []string
+		want []string
+	}{
+		{"linux", []string{"top"}, []string{"top", "-b", "-n", "1"}},
+		{"linux", []string{"/usr/bin/top", "-o", "%MEM"}, []string{"/usr/bin/top", "-o", "%MEM", "-b", "-n", "1"}},
+		{"linux", []string{"top", "-bn1"}, []string{"top", "-bn1"}},
+		{"linux", []string{"top", "-b", "-n", "1"}, []string{"top", "-b", "-n", "1"}},
+		{"darwin", []string{"top"}, []string{"top", "-l", "1"}},
+		{"darwin", []string{"top", "-l", "1"}, []string{"top", "-l", "1"}},
+		{"linux", []string{"iotop", "-o"}, []string{"iotop", "-o", "-b", "-n", "1"}},
+		{"linux", []string{"uptime"}, []string{"uptime"}},
+	}
+	for _, tt := range tests {
+		goos = tt.goos
+		if got := withBatchFlags(tt.cmd); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: withBatchFlags(%q) = %q, want %q", tt.goos, tt.cmd, got, tt.want)
+		}
+	}
+
+	if w := ttyWarning([]string{"htop"}); !strings.Contains(w, "htop expects a terminal") {
+		t.Errorf("ttyWarning(htop) = %q", w)
+	}
+	if w := ttyWarning([]string{"top"}); w != "" {
+		t.Errorf("ttyWarning(top) = %q, want none for a tool with a batch mode", w)
+	}
+}
+
+func TestValidateTabFilter(t *testing.T) {
+	tab := validateTab(Tab{Title: "cpu", Cmd: []string{"echo", "cpu"}, Filter: "^cpu"})
+	if tab.Disabled {
+		t.Fatalf("expected valid filter to keep tab enabled: %s", tab.DisabledMsg)
+	}
+	if tab.FilterRe == nil || !tab.FilterRe.MatchString("cpu0") {
+		t.Errorf("expected compiled filter matching cpu0")
+	}
+
+	tab = validateTab(Tab{Title: "cpu", Cmd: []string{"echo", "cpu"}, Filter: "([a-z"})
+	if !tab.Disabled {
+		t.Fatalf("expected invalid filter to disable tab")
+	}
+	if !strings.Contains(tab.DisabledMsg, "Invalid filter") {
+		t.Errorf("expected invalid filter message, got %q", tab.DisabledMsg)
+	}
+}
+
+func TestValidateTabAccent(t *testing.T) {
+	for _, accent := range []string{"#f87171", "#F00"} {
+		tab := validateTab(Tab{Title: "logs", Cmd: []string{"echo", "logs"}, Accent: accent})
+		if tab.Disabled {
+			t.Errorf("accent %q: expected tab enabled, got %q", accent, tab.DisabledMsg)
+		}
+	}
+	for _, accent := range []string{"red", "#ff00", "f87171"} {
+		tab := validateTab(Tab{Title: "logs", Cmd: []string{"echo", "logs"}, Accent: accent})
+		if !tab.Disabled {
+			t.Errorf("accent %q: expected tab disabled", accent)
+		}
+	}
+}
+
+func TestEnsureFileWritesLoadableStarter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "perfdeck.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	if got := Path(); got != path {
+		t.Fatalf("Path() = %q, want %q", got, path)
+	}
+	if err := EnsureFile(path); err != nil {
+		t.Fatalf("EnsureFile: %v", err)
+	}
+	cfg, tabs := Load(LoadOptions{})
+	if len(cfg.Tabs) == 0 || len(tabs) != len(cfg.Tabs) {
+		t.Errorf("starter config should define its own tabs, got %d (from file %d)", len(tabs), len(cfg.Tabs))
+	}
+	var got, want []string
+	for _, tab := range tabs {
+		got = append(got, tab.Title)
+	}
+	for _, tab := range buildDefaultTabs(cfg.GlobalRefreshInterval) {
+		want = append(want, tab.Title)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("starter tabs = %q, want the defaults %q", got, want)
+	}
+	if cfg.GlobalRefreshInterval.Duration != defaultRefreshInterval {
+		t.Errorf("starter global_refresh_interval = %s, want %s", cfg.GlobalRefreshInterval.Duration, defaultRefreshInterval)
+	}
+
+	if err := os.WriteFile(path, []byte("prefetch = true\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := EnsureFile(path); err != nil {
+		t.Fatalf("EnsureFile on existing file: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "prefetch = true\n" {
+		t.Errorf("EnsureFile overwrote an existing config: %q", data)
+	}
+}
+
+func TestLoadStaticTabs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perfdeck.toml")
+	data := `global_refresh_interval = "3s"
+
+[[tab]]
+title = "zero"
+cmd = ["echo", "zero"]
+refresh_interval = "0"
+
+[[tab]]
+title = "flag"
+cmd = ["echo", "flag"]
+static = true
+
+[[tab]]
+title = "live"
+cmd = ["echo", "live"]
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	_, tabs := Load(LoadOptions{})
+	if len(tabs) != 3 {
+		t.Fatalf("expected 3 tabs, got %d", len(tabs))
+	}
+	for _, tab := range tabs {
+		wantStatic := tab.Title != "live"
+		if tab.Static != wantStatic {
+			t.Errorf("%s: static = %t, want %t", tab.Title, tab.Static, wantStatic)
+		}
+		if tab.RefreshInterval.Duration != 3*time.Second {
+			t.Errorf("%s: refresh = %s, want the global interval", tab.Title, tab.RefreshInterval.Duration)
+		}
+	}
+}
+
+func TestValidateTabCmds(t *testing.T) {
+	tab := validateTab(Tab{Title: "mem", Cmds: [][]string{{"echo", "-m"}, {}, {"echo", "-h"}}})
+	if tab.Disabled {
+		t.Fatalf("expected cmds-only tab to be enabled: %s", tab.DisabledMsg)
+	}
+	if strings.Join(tab.Cmd, " ") != "echo -m" || len(tab.Cmds) != 2 {
+		t.Errorf("expected first variant as cmd and empty variants dropped, got %v / %v", tab.Cmd, tab.Cmds)
+	}
+
+	tab = validateTab(Tab{Title: "mem", Cmd: []string{"echo", "-k"}, Cmds: [][]string{{"echo", "-m"}}})
+	if len(tab.Cmds) != 2 || strings.Join(tab.Cmds[0], " ") != "echo -k" {
+		t.Errorf("expected explicit cmd as the first variant, got %v", tab.Cmds)
+	}
+}
+
+func TestXDGConfigHome(t *testing.T) {
+	xdg := t.TempDir()
+	t.Setenv("PERFDECK_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	path := filepath.Join(xdg, "perfdeck", "config.toml")
+	if got := configPaths()[0]; got != path {
+		t.Fatalf("configPaths()[0] = %q, want %q", got, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("[[tab]]\ntitle = \"xdg\"\ncmd = [\"echo\", \"hi\"]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := Path(); got != path {
+		t.Errorf("Path() = %q, want %q", got, path)
+	}
+	if _, tabs := Load(LoadOptions{}); len(tabs) != 1 || tabs[0].Title != "xdg" {
+		t.Errorf("Load() did not read the XDG config, got %+v", tabs)
+	}
+
+	// PERFDECK_CONFIG still wins.
+	t.Setenv("PERFDECK_CONFIG", "/tmp/explicit.toml")
+	if got := configPaths()[0]; got != "/tmp/explicit.toml" {
+		t.Errorf("configPaths()[0] = %q, want $PERFDECK_CONFIG", got)
+	}
+}
+
+func TestResolvedPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	t.Setenv("HOME", dir)
+	path := filepath.Join(dir, "custom.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	if got, ok := ResolvedPath(); ok {
+		t.Fatalf("ResolvedPath() = %q, true; want none found", got)
+	}
+	if err := os.WriteFile(path, []byte("global_refresh_interval = \"5s\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := ResolvedPath(); !ok || got != path {
+		t.Errorf("ResolvedPath() = %q, %t; want %q, true", got, ok, path)
+	}
+}
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"~", home},
+		{"~/cfg.toml", filepath.Join(home, "cfg.toml")},
+		{"~/a/b/", filepath.Join(home, "a", "b")},
+		{"/etc/perfdeck.toml", "/etc/perfdeck.toml"},
+		{"~other/cfg.toml", "~other/cfg.toml"},
+		{"rel/~/x", "rel/~/x"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ExpandHome(tt.input); got != tt.expected {
+			t.Errorf("ExpandHome(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+
+	t.Setenv("PERFDECK_CONFIG", "~/cfg.toml")
+	if got := configPaths()[0]; got != filepath.Join(home, "cfg.toml") {
+		t.Errorf("configPaths()[0] = %q, want the expanded $PERFDECK_CONFIG", got)
+	}
+}
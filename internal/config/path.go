@@ -0,0 +1,108 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// starterHeader opens the starter config with the settings most worth
+// knowing about.
+const starterHeader = `# perfdeck configuration. Remove a [[tab]] block to hide it.
+# net_unit = "bits"
+# prefetch = true
+# layout = "bottom"
+`
+
+// starterTab is the part of a default tab written to the starter config;
+// refresh intervals are left to global_refresh_interval.
+type starterTab struct {
+	Title   string   `toml:"title"`
+	Cmd     []string `toml:"cmd,omitempty"`
+	Builtin string   `toml:"builtin,omitempty"`
+	Static  bool     `toml:"static,omitempty"`
+}
+
+// starterConfig is written when the user asks to edit a config that does
+// not exist yet. It lists the default tabs for this platform, so editing
+// starts from what perfdeck was already showing.
+func starterConfig() (string, error) {
+	var file struct {
+		GlobalRefreshInterval string       `toml:"global_refresh_interval"`
+		Tabs                  []starterTab `toml:"tab"`
+	}
+	file.GlobalRefreshInterval = defaultRefreshInterval.String()
+	for _, t := range defaultTabs(duration{Duration: defaultRefreshInterval}) {
+		file.Tabs = append(file.Tabs, starterTab{Title: t.Title, Cmd: t.Cmd, Builtin: t.Builtin, Static: t.Static})
+	}
+	var b strings.Builder
+	b.WriteString(starterHeader)
+	enc := toml.NewEncoder(&b)
+	enc.Indent = ""
+	if err := enc.Encode(file); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// ResolvedPath returns the config file Load reads from, the first
+// candidate that exists. It reports false when none does and Load falls
+// back to the defaults.
+func ResolvedPath() (string, bool) {
+	for _, path := range configPaths() {
+		if _, err := os.Stat(path); err == nil {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// Path returns the config file Load reads from or, when none exists, the
+// location a new one should be created at.
+func Path() string {
+	if path, ok := ResolvedPath(); ok {
+		return path
+	}
+	// configPaths ends with the working-directory fallback; prefer
+	// $PERFDECK_CONFIG or the user config dir for a new file.
+	return configPaths()[0]
+}
+
+// EnsureFile writes a starter config at path unless a file is already
+// there.
+func EnsureFile(path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	starter, err := starterConfig()
+	if err != nil {
+		return fmt.Errorf("write starter config: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(starter), 0o644); err != nil {
+		return fmt.Errorf("write starter config: %w", err)
+	}
+	return nil
+}
+
+// ExpandHome replaces a leading "~" or "~/" in path with the user's home
+// directory, since shells don't expand it inside quotes or config values.
+// Other paths, and "~user" forms, are returned unchanged.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
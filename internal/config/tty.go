@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// batchFlags maps tools that draw a full-screen UI when left alone to the
+// arguments that make them print one snapshot and exit. Tabs run without a
+// terminal, so these are added when the command has no batch flag yet.
+var batchFlags = map[string][]string{
+	"top":   {"-b", "-n", "1"},
+	"iotop": {"-b", "-n", "1"},
+	"atop":  {"-1"},
+}
+
+// darwinBatchFlags overrides batchFlags for macOS tools.
+var darwinBatchFlags = map[string][]string{
+	"top": {"-l", "1"},
+}
+
+// interactiveTools need a terminal and have no batch mode to fall back on.
+var interactiveTools = map[string]bool{
+	"htop":  true,
+	"btop":  true,
+	"watch": true,
+	"less":  true,
+	"more":  true,
+	"vi":    true,
+	"vim":   true,
+	"nano":  true,
+}
+
+// withBatchFlags appends the batch flags for a known full-screen tool
+// unless cmd already passes the first of them, e.g. "top" becomes
+// "top -b -n 1" but "top -bn1" is left alone.
+func withBatchFlags(cmd []string) []string {
+	if len(cmd) == 0 {
+		return cmd
+	}
+	name := filepath.Base(cmd[0])
+	flags, ok := batchFlags[name]
+	if goos == osDarwin {
+		if darwin, dok := darwinBatchFlags[name]; dok {
+			flags, ok = darwin, true
+		}
+	}
+	if !ok {
+		return cmd
+	}
+	for _, arg := range cmd[1:] {
+		if strings.HasPrefix(arg, flags[0]) {
+			return cmd
+		}
+	}
+	return append(append([]string(nil), cmd...), flags...)
+}
+
+// ttyWarning explains that cmd likely needs a terminal, or returns "".
+func ttyWarning(cmd []string) string {
+	if len(cmd) == 0 || !interactiveTools[filepath.Base(cmd[0])] {
+		return ""
+	}
+	return fmt.Sprintf("%s expects a terminal and may hang or print nothing here", filepath.Base(cmd[0]))
+}
@@ -0,0 +1,26 @@
+package export
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// healthStatus is the /healthz response body.
+type healthStatus struct {
+	Status string `json:"status"`
+	// Metrics maps each metric to whether one of its sources is available.
+	Metrics map[string]bool `json:"metrics"`
+}
+
+// HealthHandler serves /healthz: always 200 while the process is up, with
+// the availability of each metric source, e.g. monitor.AvailableMetrics,
+// so orchestrators can health-check a headless perfdeck.
+func HealthHandler(available func() map[string]bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(healthStatus{Status: "ok", Metrics: available()}); err != nil {
+			log.Printf("healthz: %v", err)
+		}
+	})
+}
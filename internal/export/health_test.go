@@ -0,0 +1,43 @@
+package export
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", HealthHandler(func() map[string]bool {
+		return map[string]bool{"cpu": true, "memory": true, "network": false}
+	}))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var body struct {
+		Status  string          `json:"status"`
+		Metrics map[string]bool `json:"metrics"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Status != "ok" {
+		t.Errorf("status field = %q, want ok", body.Status)
+	}
+	if !body.Metrics["cpu"] || !body.Metrics["memory"] || body.Metrics["network"] {
+		t.Errorf("metrics = %v", body.Metrics)
+	}
+}
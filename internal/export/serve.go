@@ -0,0 +1,88 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+// shutdownGrace bounds how long Serve waits for open requests once its
+// context is cancelled.
+const shutdownGrace = 5 * time.Second
+
+// Latest holds the most recent sample for the HTTP handlers, which run on
+// other goroutines than the sampler.
+type Latest struct {
+	mu     sync.Mutex
+	sample monitor.MetricsSample
+}
+
+// Set records s as the latest sample.
+func (l *Latest) Set(s monitor.MetricsSample) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sample = s
+}
+
+// Get returns the latest sample, the zero sample before the first Set.
+func (l *Latest) Get() monitor.MetricsSample {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.sample
+}
+
+// MetricsHandler serves the available metrics of latest in the Prometheus
+// text format.
+func MetricsHandler(latest func() monitor.MetricsSample) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := latest()
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		gauge := func(name, help string, v float64, ok bool) {
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "# HELP perfdeck_%s %s\n# TYPE perfdeck_%s gauge\nperfdeck_%s %g\n", name, help, name, name, v)
+		}
+		gauge("cpu_percent", "CPU busy percentage.", s.CPU, s.OkCPU)
+		gauge("memory_percent", "Memory used percentage.", s.Mem, s.OkMem)
+		gauge("load1", "1-minute load average.", s.Load, s.OkLoad)
+		gauge("load5", "5-minute load average.", s.Load5, s.OkLoad)
+		gauge("load15", "15-minute load average.", s.Load15, s.OkLoad)
+		gauge("network_bytes_per_second", "Network receive plus transmit rate.", s.NetKB*1024, s.OkNet)
+	})
+}
+
+// NewMux routes /metrics and /healthz.
+func NewMux(latest func() monitor.MetricsSample, available func() map[string]bool) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", MetricsHandler(latest))
+	mux.Handle("/healthz", HealthHandler(available))
+	return mux
+}
+
+// Serve serves h on ln until ctx is cancelled, then shuts down gracefully.
+func Serve(ctx context.Context, ln net.Listener, h http.Handler) error {
+	srv := &http.Server{Handler: h, ReadHeaderTimeout: 5 * time.Second}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	select {
+	case err := <-errc:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownGrace)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("serve: %w", err)
+		}
+		if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serve: %w", err)
+		}
+		return nil
+	}
+}
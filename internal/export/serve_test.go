@@ -0,0 +1,72 @@
+package export
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+func TestServe(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var latest Latest
+	latest.Set(monitor.MetricsSample{CPU: 42.5, Load: 1.5, Load5: 1, Load15: 0.5, NetKB: 2, OkCPU: true, OkLoad: true, OkNet: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, ln, NewMux(latest.Get, func() map[string]bool { return map[string]bool{"cpu": true} }))
+	}()
+
+	get := func(path string) string {
+		t.Helper()
+		resp, err := http.Get("http://" + ln.Addr().String() + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200", path, resp.StatusCode)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+
+	metrics := get("/metrics")
+	for _, want := range []string{
+		"# TYPE perfdeck_cpu_percent gauge\nperfdeck_cpu_percent 42.5\n",
+		"perfdeck_load1 1.5\n",
+		"perfdeck_load15 0.5\n",
+		"perfdeck_network_bytes_per_second 2048\n",
+	} {
+		if !strings.Contains(metrics, want) {
+			t.Errorf("/metrics missing %q:\n%s", want, metrics)
+		}
+	}
+	if strings.Contains(metrics, "memory_percent") {
+		t.Errorf("/metrics reports memory that was not sampled:\n%s", metrics)
+	}
+	if health := get("/healthz"); !strings.Contains(health, `"cpu":true`) {
+		t.Errorf("/healthz = %q", health)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Serve after cancel = %v, want nil", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve did not return after cancel")
+	}
+}
@@ -0,0 +1,107 @@
+// Package export forwards metric samples to external collectors: statsd,
+// and Prometheus scrapes in serve mode.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+// maxPacket keeps each datagram under a typical Ethernet MTU so it is not
+// fragmented or dropped.
+const maxPacket = 1432
+
+// StatsdClient sends gauges to a statsd server over UDP. Gauges are
+// buffered and written in as few packets as possible on Flush.
+type StatsdClient struct {
+	conn   net.Conn
+	prefix string
+
+	mu  sync.Mutex
+	buf []byte
+	// err is a write that failed while Gauge made room in the buffer,
+	// returned by the next Flush.
+	err error
+}
+
+// NewStatsdClient dials addr ("host:port"). Every metric name is prefixed
+// with prefix and a dot, e.g. "perfdeck.cpu".
+func NewStatsdClient(addr, prefix string) (*StatsdClient, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("statsd: %w", err)
+	}
+	return &StatsdClient{conn: conn, prefix: prefix}, nil
+}
+
+// Gauge queues "prefix.name:v|g", flushing first when the line would
+// overflow the current packet. A failed write there is reported by the next
+// Flush.
+func (c *StatsdClient) Gauge(name string, v float64) {
+	line := c.prefix + "." + name + ":" + strconv.FormatFloat(v, 'f', -1, 64) + "|g"
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.buf) > 0 && len(c.buf)+1+len(line) > maxPacket {
+		if err := c.flushLocked(); err != nil && c.err == nil {
+			c.err = err
+		}
+	}
+	if len(c.buf) > 0 {
+		c.buf = append(c.buf, '\n')
+	}
+	c.buf = append(c.buf, line...)
+}
+
+// Flush writes any queued gauges as one packet. It also returns a write
+// that failed in Gauge since the last Flush.
+func (c *StatsdClient) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	err := errors.Join(c.err, c.flushLocked())
+	c.err = nil
+	return err
+}
+
+func (c *StatsdClient) flushLocked() error {
+	if len(c.buf) == 0 {
+		return nil
+	}
+	_, err := c.conn.Write(c.buf)
+	c.buf = c.buf[:0]
+	if err != nil {
+		return fmt.Errorf("statsd: %w", err)
+	}
+	return nil
+}
+
+// Sample sends the available metrics of s as gauges and flushes them.
+func (c *StatsdClient) Sample(s monitor.MetricsSample) error {
+	if s.OkCPU {
+		c.Gauge("cpu", s.CPU)
+	}
+	if s.OkMem {
+		c.Gauge("mem", s.Mem)
+	}
+	if s.OkLoad {
+		c.Gauge("load", s.Load)
+	}
+	if s.OkNet {
+		c.Gauge("net_kb", s.NetKB)
+	}
+	return c.Flush()
+}
+
+// Close flushes pending gauges and closes the connection.
+func (c *StatsdClient) Close() error {
+	flushErr := c.Flush()
+	if err := c.conn.Close(); err != nil {
+		return err
+	}
+	return flushErr
+}
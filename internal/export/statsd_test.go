@@ -0,0 +1,109 @@
+package export
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+func listen(t *testing.T) *net.UDPConn {
+	t.Helper()
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readPacket(t *testing.T, conn *net.UDPConn) string {
+	t.Helper()
+	buf := make([]byte, 65536)
+	if err := conn.SetReadDeadline(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func TestStatsdSample(t *testing.T) {
+	srv := listen(t)
+	c, err := NewStatsdClient(srv.LocalAddr().String(), "perfdeck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	s := monitor.MetricsSample{CPU: 42.5, Mem: 60, Load: 1.25, NetKB: 512, OkCPU: true, OkMem: true, OkLoad: true, OkNet: true}
+	if err := c.Sample(s); err != nil {
+		t.Fatalf("Sample: %v", err)
+	}
+	want := "perfdeck.cpu:42.5|g\nperfdeck.mem:60|g\nperfdeck.load:1.25|g\nperfdeck.net_kb:512|g"
+	if got := readPacket(t, srv); got != want {
+		t.Errorf("packet = %q, want %q", got, want)
+	}
+
+	// Missing metrics are left out rather than sent as zero.
+	if err := c.Sample(monitor.MetricsSample{Load: 0.5, OkLoad: true}); err != nil {
+		t.Fatalf("Sample: %v", err)
+	}
+	if got := readPacket(t, srv); got != "perfdeck.load:0.5|g" {
+		t.Errorf("packet = %q, want only the load gauge", got)
+	}
+}
+
+func TestStatsdBatchesUnderMaxPacket(t *testing.T) {
+	srv := listen(t)
+	c, err := NewStatsdClient(srv.LocalAddr().String(), "perfdeck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	const gauges = 200
+	for i := 0; i < gauges; i++ {
+		c.Gauge("metric", float64(i))
+	}
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	lines := 0
+	for lines < gauges {
+		p := readPacket(t, srv)
+		if len(p) > maxPacket {
+			t.Fatalf("packet of %d bytes exceeds %d", len(p), maxPacket)
+		}
+		lines += len(strings.Split(p, "\n"))
+	}
+	if lines != gauges {
+		t.Errorf("received %d gauges, want %d", lines, gauges)
+	}
+}
+
+// failConn is a net.Conn whose writes always fail.
+type failConn struct{ net.Conn }
+
+func (failConn) Write([]byte) (int, error) { return 0, errors.New("network is unreachable") }
+
+func TestStatsdReportsMidBatchWriteError(t *testing.T) {
+	c := &StatsdClient{conn: failConn{}, prefix: "perfdeck"}
+	// Enough gauges that Gauge has to write a full packet itself.
+	for i := 0; i < 200; i++ {
+		c.Gauge("metric", float64(i))
+	}
+	c.buf = c.buf[:0]
+	if err := c.Flush(); err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Errorf("Flush = %v, want the write error from the batch", err)
+	}
+	if err := c.Flush(); err != nil {
+		t.Errorf("second Flush = %v, want the error reported once", err)
+	}
+}
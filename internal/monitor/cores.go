@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// The core counts, probed on first use; tests clear coresProbed to probe
+// again.
+var (
+	coresMu       sync.Mutex
+	coresProbed   bool
+	coresLogical  int
+	coresPhysical int
+)
+
+// getCoreCounts returns the logical CPUs and the physical cores behind
+// them. Without /proc/cpuinfo the logical count comes from the Go runtime
+// and physical is 0 (unknown). The counts are probed once.
+func getCoreCounts() (logical, physical int) {
+	coresMu.Lock()
+	defer coresMu.Unlock()
+	if !coresProbed {
+		coresLogical, coresPhysical = 0, 0
+		if data, err := readFile("/proc/cpuinfo"); err == nil {
+			coresLogical, coresPhysical = parseCPUInfo(data)
+		}
+		if coresLogical == 0 {
+			coresLogical = runtime.NumCPU()
+		}
+		coresProbed = true
+	}
+	return coresLogical, coresPhysical
+}
+
+// parseCPUInfo counts processor entries in /proc/cpuinfo, and physical
+// cores as the distinct (physical id, core id) pairs. Physical is 0 when
+// the file has no topology fields, as on many ARM kernels.
+func parseCPUInfo(data []byte) (logical, physical int) {
+	type core struct{ pkg, id string }
+	cores := make(map[core]bool)
+	var cur core
+	var haveID bool
+	flush := func() {
+		if haveID {
+			cores[cur] = true
+		}
+		cur, haveID = core{}, false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "processor":
+			flush()
+			logical++
+		case "physical id":
+			cur.pkg = value
+		case "core id":
+			cur.id = value
+			haveID = true
+		}
+	}
+	flush()
+	return logical, len(cores)
+}
+
+// formatCores renders core counts, e.g. "8 cores (4 physical)", leaving
+// out the physical count when it is unknown or the same.
+func formatCores(logical, physical int) string {
+	s := fmt.Sprintf("%d cores", logical)
+	if logical == 1 {
+		s = "1 core"
+	}
+	if physical > 0 && physical != logical {
+		s += fmt.Sprintf(" (%d physical)", physical)
+	}
+	return s
+}
@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestParseCPUInfo(t *testing.T) {
+	// Two packages of two cores with two threads each.
+	var smt string
+	for i, c := range [][2]string{{"0", "0"}, {"0", "1"}, {"1", "0"}, {"1", "1"}, {"0", "0"}, {"0", "1"}, {"1", "0"}, {"1", "1"}} {
+		smt += "processor\t: " + string(rune('0'+i)) + "\nmodel name\t: Test CPU\nphysical id\t: " + c[0] + "\ncore id\t\t: " + c[1] + "\n\n"
+	}
+	tests := []struct {
+		name              string
+		data              string
+		logical, physical int
+	}{
+		{"smt", smt, 8, 4},
+		{"no topology", "processor\t: 0\nBogoMIPS\t: 48.00\n\nprocessor\t: 1\nBogoMIPS\t: 48.00\n", 2, 0},
+		{"empty", "", 0, 0},
+	}
+	for _, tt := range tests {
+		logical, physical := parseCPUInfo([]byte(tt.data))
+		if logical != tt.logical || physical != tt.physical {
+			t.Errorf("%s: parseCPUInfo = %d, %d; want %d, %d", tt.name, logical, physical, tt.logical, tt.physical)
+		}
+	}
+}
+
+func TestFormatCores(t *testing.T) {
+	tests := []struct {
+		logical, physical int
+		want              string
+	}{
+		{8, 4, "8 cores (4 physical)"},
+		{4, 4, "4 cores"},
+		{4, 0, "4 cores"},
+		{1, 1, "1 core"},
+	}
+	for _, tt := range tests {
+		if got := formatCores(tt.logical, tt.physical); got != tt.want {
+			t.Errorf("formatCores(%d, %d) = %q, want %q", tt.logical, tt.physical, got, tt.want)
+		}
+	}
+}
+
+// resetCoreCounts makes the next getCoreCounts probe again, and again after
+// the test.
+func resetCoreCounts(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		coresMu.Lock()
+		coresProbed = false
+		coresMu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestGetCoreCounts(t *testing.T) {
+	origReadFile := readFile
+	t.Cleanup(func() { readFile = origReadFile })
+
+	resetCoreCounts(t)
+	readFile = func(string) ([]byte, error) {
+		return []byte("processor\t: 0\nphysical id\t: 0\ncore id\t: 0\n\nprocessor\t: 1\nphysical id\t: 0\ncore id\t: 0\n"), nil
+	}
+	if logical, physical := getCoreCounts(); logical != 2 || physical != 1 {
+		t.Errorf("getCoreCounts = %d, %d; want 2, 1", logical, physical)
+	}
+
+	resetCoreCounts(t)
+	readFile = func(string) ([]byte, error) { return nil, os.ErrNotExist }
+	if logical, physical := getCoreCounts(); logical != runtime.NumCPU() || physical != 0 {
+		t.Errorf("without /proc/cpuinfo: getCoreCounts = %d, %d; want %d, 0", logical, physical, runtime.NumCPU())
+	}
+}
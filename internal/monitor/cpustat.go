@@ -0,0 +1,174 @@
+package monitor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cpuTimes holds the aggregate jiffy counters from the "cpu" line of
+// /proc/stat. idle includes iowait, which is time the CPU sat unused.
+type cpuTimes struct {
+	idle   uint64
+	iowait uint64
+	steal  uint64
+	total  uint64
+}
+
+// cpuUsage is a utilization reading in percent. IOWait and Steal are only
+// known when detail is set by a source that reports them (/proc/stat).
+type cpuUsage struct {
+	busy   float64
+	iowait float64
+	steal  float64
+	detail bool
+}
+
+// procStatPath is a var so tests can point the reader at a missing file.
+var procStatPath = "/proc/stat"
+
+// cpuSettle is the gap between the two /proc/stat readings taken when
+// there is no baseline from a previous sample yet.
+var cpuSettle = 100 * time.Millisecond
+
+var (
+	cpuMu   sync.Mutex
+	cpuPrev cpuTimes
+	cpuHave bool
+)
+
+// The latest background reading from the interval tools, for platforms
+// without /proc/stat.
+var (
+	intervalMu      sync.Mutex
+	intervalRunning bool
+	intervalHave    bool
+	intervalLast    cpuUsage
+	intervalOk      bool
+	intervalReason  string
+)
+
+// cpuFromInterval returns the latest reading from vmstat or mpstat without
+// waiting for one, and starts the next run unless one is in flight, so a
+// reading is at most one sample old. The first call has nothing yet and
+// reports reasonWarming. The run outlives ctx, which usually ends with the
+// sample, but runQuickCmd bounds it.
+func cpuFromInterval(ctx context.Context) (cpuUsage, bool, string) {
+	_, errVmstat := lookPath("vmstat")
+	_, errMpstat := lookPath("mpstat")
+	if errVmstat != nil && errMpstat != nil {
+		return cpuUsage{}, false, notFound("vmstat and mpstat")
+	}
+	intervalMu.Lock()
+	defer intervalMu.Unlock()
+	if !intervalRunning {
+		intervalRunning = true
+		go func(ctx context.Context) {
+			cpu, ok, reason := intervalCPU(ctx)
+			intervalMu.Lock()
+			defer intervalMu.Unlock()
+			intervalLast, intervalOk, intervalReason = cpu, ok, reason
+			intervalHave, intervalRunning = true, false
+		}(context.WithoutCancel(ctx))
+	}
+	if !intervalHave {
+		return cpuUsage{}, false, reasonWarming
+	}
+	return intervalLast, intervalOk, intervalReason
+}
+
+// cpuFromProcStat computes utilization from the change in /proc/stat
+// counters since the previous call, like the network rate. The first call
+// has no baseline, so it takes a second reading cpuSettle later. The
+// reason is empty when /proc/stat does not exist.
+func cpuFromProcStat(ctx context.Context) (cpuUsage, bool, string) {
+	cur, ok, reason := readProcStat()
+	if !ok {
+		return cpuUsage{}, false, reason
+	}
+	cpuMu.Lock()
+	defer cpuMu.Unlock()
+	prev, had := cpuPrev, cpuHave
+	if !had {
+		select {
+		case <-time.After(cpuSettle):
+		case <-ctx.Done():
+			return cpuUsage{}, false, reasonWarming
+		}
+		prev = cur
+		if cur, ok, reason = readProcStat(); !ok {
+			return cpuUsage{}, false, reason
+		}
+	}
+	cpuPrev, cpuHave = cur, true
+	usage, ok := cpuBusy(prev, cur)
+	if !ok {
+		return cpuUsage{}, false, reasonWarming
+	}
+	return usage, true, ""
+}
+
+func readProcStat() (cpuTimes, bool, string) {
+	data, err := readFile(procStatPath)
+	if err != nil {
+		return cpuTimes{}, false, ""
+	}
+	t, ok := parseProcStat(data)
+	if !ok {
+		return cpuTimes{}, false, parseFailed("/proc/stat")
+	}
+	return t, true, ""
+}
+
+// parseProcStat reads the aggregate line, e.g.
+// "cpu  4705 356 584 3699 23 23 0 0 0 0".
+func parseProcStat(data []byte) (cpuTimes, bool) {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		var t cpuTimes
+		// Guest time is already counted in user and nice, so stop at steal.
+		for i, f := range fields[1:min(len(fields), 9)] {
+			v, err := strconv.ParseUint(f, 10, 64)
+			if err != nil {
+				return cpuTimes{}, false
+			}
+			t.total += v
+			switch i {
+			case 3:
+				t.idle += v
+			case 4:
+				t.idle += v
+				t.iowait = v
+			case 7:
+				t.steal = v
+			}
+		}
+		return t, true
+	}
+	return cpuTimes{}, false
+}
+
+// cpuBusy returns the busy, iowait and steal percentages between two
+// readings. It reports false when the counters did not advance or went
+// backwards.
+func cpuBusy(prev, cur cpuTimes) (cpuUsage, bool) {
+	if cur.total <= prev.total || cur.idle < prev.idle || cur.iowait < prev.iowait || cur.steal < prev.steal {
+		return cpuUsage{}, false
+	}
+	total := float64(cur.total - prev.total)
+	idle := float64(cur.idle - prev.idle)
+	if idle > total {
+		return cpuUsage{}, false
+	}
+	return cpuUsage{
+		busy:   (total - idle) / total * 100,
+		iowait: float64(cur.iowait-prev.iowait) / total * 100,
+		steal:  float64(cur.steal-prev.steal) / total * 100,
+		detail: true,
+	}, true
+}
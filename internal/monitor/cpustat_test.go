@@ -0,0 +1,251 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseProcStat(t *testing.T) {
+	data := []byte("cpu  100 0 50 800 50 0 0 4 7 0\ncpu0 50 0 25 400 25 0 0 0 0 0\nintr 12345\n")
+	got, ok := parseProcStat(data)
+	if !ok {
+		t.Fatal("parseProcStat reported failure")
+	}
+	want := cpuTimes{idle: 850, iowait: 50, steal: 4, total: 1004}
+	if got != want {
+		t.Errorf("parseProcStat = %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{"", "cpu0 1 2 3 4\n", "cpu 1 2 x 4 5\n"} {
+		if _, ok := parseProcStat([]byte(bad)); ok {
+			t.Errorf("parseProcStat(%q) should fail", bad)
+		}
+	}
+}
+
+func TestCPUBusyFromProcStat(t *testing.T) {
+	first, _ := parseProcStat([]byte("cpu  100 0 50 800 50 0 0 10 0 0\n"))
+	second, _ := parseProcStat([]byte("cpu  150 0 70 900 60 0 0 30 0 0\n"))
+
+	// 200 jiffies elapsed: 110 idle or iowait (10 iowait) and 20 stolen.
+	got, ok := cpuBusy(first, second)
+	if !ok {
+		t.Fatal("cpuBusy reported failure")
+	}
+	for _, c := range []struct {
+		name      string
+		got, want float64
+	}{
+		{"busy", got.busy, 45},
+		{"iowait", got.iowait, 5},
+		{"steal", got.steal, 10},
+	} {
+		if math.Abs(c.got-c.want) > 0.001 {
+			t.Errorf("cpuBusy %s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	if !got.detail {
+		t.Error("cpuBusy should mark the iowait/steal breakdown as known")
+	}
+	if _, ok := cpuBusy(second, first); ok {
+		t.Error("cpuBusy should fail when counters go backwards")
+	}
+	if _, ok := cpuBusy(first, first); ok {
+		t.Error("cpuBusy should fail when no time elapsed")
+	}
+}
+
+// resetCPUBaseline forgets the previous /proc/stat reading.
+func resetCPUBaseline(t *testing.T) {
+	t.Helper()
+	cpuMu.Lock()
+	cpuPrev, cpuHave = cpuTimes{}, false
+	cpuMu.Unlock()
+	t.Cleanup(func() {
+		cpuMu.Lock()
+		cpuPrev, cpuHave = cpuTimes{}, false
+		cpuMu.Unlock()
+	})
+}
+
+func TestGetCPUUsageFollowsProcStat(t *testing.T) {
+	origLookPath, origExecCmd, origPath := lookPath, execCmd, procStatPath
+	t.Cleanup(func() { lookPath, execCmd, procStatPath = origLookPath, origExecCmd, origPath })
+	resetCPUBaseline(t)
+
+	lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	var ran [][]string
+	execCmd = func(ctx context.Context, cmd []string) (string, error) {
+		ran = append(ran, cmd)
+		return "", errors.New("should not run")
+	}
+	procStatPath = filepath.Join(t.TempDir(), "stat")
+	write := func(user, idle int) {
+		t.Helper()
+		line := fmt.Sprintf("cpu  %d 0 0 %d 0 0 0 0 0 0\n", user, idle)
+		if err := os.WriteFile(procStatPath, []byte(line), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each step adds 100 jiffies, busy for the given share of them.
+	user, idle := 1000, 1000
+	write(user, idle)
+	if _, ok, _ := getCPUUsage(context.Background()); ok {
+		t.Error("getCPUUsage reported a value from counters that did not move")
+	}
+	for _, busy := range []int{90, 10, 50} {
+		user, idle = user+busy, idle+100-busy
+		write(user, idle)
+		start := time.Now()
+		cpu, ok, _ := getCPUUsage(context.Background())
+		if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+			t.Errorf("getCPUUsage took %v with a baseline, want no delay", elapsed)
+		}
+		if !ok || math.Abs(cpu.busy-float64(busy)) > 0.001 {
+			t.Errorf("getCPUUsage = %v, %t; want %d, true", cpu.busy, ok, busy)
+		}
+	}
+	if len(ran) != 0 {
+		t.Errorf("ran %q, want no vmstat or mpstat while /proc/stat works", ran)
+	}
+}
+
+func TestGetCPUUsageFirstSample(t *testing.T) {
+	if _, err := os.Stat(procStatPath); err != nil {
+		t.Skip("no /proc/stat")
+	}
+	resetCPUBaseline(t)
+
+	start := time.Now()
+	cpu, ok, reason := getCPUUsage(context.Background())
+	if elapsed := time.Since(start); elapsed > cpuSettle+500*time.Millisecond {
+		t.Errorf("getCPUUsage took %v, want about %v", elapsed, cpuSettle)
+	}
+	if !ok || cpu.busy < 0 || cpu.busy > 100 {
+		t.Errorf("getCPUUsage = %v, %t (%s); want a percentage on the first call", cpu.busy, ok, reason)
+	}
+}
+
+// resetIntervalCPU waits for any background interval run to finish and
+// forgets its reading, now and when the test ends.
+func resetIntervalCPU(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		waitIntervalCPU(t)
+		intervalMu.Lock()
+		intervalHave, intervalLast, intervalOk, intervalReason = false, cpuUsage{}, false, ""
+		intervalMu.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+// waitIntervalCPU waits for the background interval run, if any.
+func waitIntervalCPU(t *testing.T) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		intervalMu.Lock()
+		running := intervalRunning
+		intervalMu.Unlock()
+		if !running {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background interval CPU run did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGetCPUUsageIntervalLastResort(t *testing.T) {
+	origLookPath, origExecCmd, origPath := lookPath, execCmd, procStatPath
+	t.Cleanup(func() { lookPath, execCmd, procStatPath = origLookPath, origExecCmd, origPath })
+	resetIntervalCPU(t)
+
+	procStatPath = filepath.Join(t.TempDir(), "missing")
+	lookPath = func(file string) (string, error) {
+		if file == "mpstat" {
+			return "/usr/bin/mpstat", nil
+		}
+		return "", exec.ErrNotFound
+	}
+	// mpstat 1 1 takes a second; release stands in for it finishing.
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var ran [][]string
+	execCmd = func(ctx context.Context, cmd []string) (string, error) {
+		mu.Lock()
+		ran = append(ran, cmd)
+		mu.Unlock()
+		<-release
+		return "Linux 6.1 (host)\n\n10:00:00 AM  CPU  %usr %nice %sys %iowait %irq %soft %steal %guest %gnice %idle\n10:00:00 AM  all  10.00 0.00 5.00 0.00 0.00 0.00 0.00 0.00 0.00 85.00\nAverage:     all  10.00 0.00 5.00 0.00 0.00 0.00 0.00 0.00 0.00 85.00\n", nil
+	}
+
+	start := time.Now()
+	_, ok, reason := getCPUUsage(context.Background())
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("getCPUUsage took %v while mpstat runs, want no delay", elapsed)
+	}
+	if ok || reason != reasonWarming {
+		t.Errorf("first getCPUUsage = %t, %q; want no value yet", ok, reason)
+	}
+
+	// Samples while the run is in flight neither wait nor start another.
+	start = time.Now()
+	getCPUUsage(context.Background())
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("getCPUUsage took %v while mpstat runs, want no delay", elapsed)
+	}
+
+	close(release)
+	waitIntervalCPU(t)
+	start = time.Now()
+	cpu, ok, _ := getCPUUsage(context.Background())
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("getCPUUsage took %v with a finished reading, want no delay", elapsed)
+	}
+	if !ok || math.Abs(cpu.busy-15) > 0.001 {
+		t.Errorf("getCPUUsage = %v, %t; want 15, true", cpu.busy, ok)
+	}
+	waitIntervalCPU(t)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ran) != 2 {
+		t.Errorf("ran %q, want one mpstat run per finished reading", ran)
+	}
+	for _, cmd := range ran {
+		if strings.Join(cmd, " ") != "mpstat 1 1" {
+			t.Errorf("ran %q, want the interval form of mpstat", cmd)
+		}
+	}
+}
+
+func TestParseMpstat(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want float64
+		ok   bool
+	}{
+		{"24-hour", "10:00:00     CPU    %usr   %sys   %idle\n10:00:00     all    5.00   2.50   92.50\n", 7.5, true},
+		{"12-hour", "10:00:00 AM  CPU    %usr   %sys   %idle\n10:00:00 AM  all    10.00  10.00  80.00\n", 20, true},
+		{"no all row", "10:00:00     CPU    %usr   %sys   %idle\n10:00:00     0      10.00  10.00  80.00\n", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseMpstat(tt.out)
+		if ok != tt.ok || math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("%s: parseMpstat = %v, %t; want %v, %t", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
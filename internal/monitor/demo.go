@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// Demo makes SampleMetrics and SampleSystem return deterministic synthetic
+// data instead of probing the host, for demos, screenshots and tests.
+var Demo bool
+
+const demoMemTotalMB = 8192
+
+// demoStep advances once per demo metrics sample so successive samples
+// trace the same curves on every run.
+var demoStep atomic.Int64
+
+// demoMetrics fakes a sample: sine-wave CPU, memory and load, and a
+// pseudo-random network rate that repeats every 97 samples.
+func demoMetrics() MetricsSample {
+	n := float64(demoStep.Add(1))
+	load := 1.5 + math.Sin(n/7)
+	memUsed := 5120 + 660*math.Sin(n/17)
+	return MetricsSample{
+		CPU:         50 + 35*math.Sin(n/5),
+		IOWait:      3 + 2*math.Sin(n/3),
+		Mem:         memUsed / demoMemTotalMB * 100,
+		MemUsedMB:   memUsed,
+		MemTotalMB:  demoMemTotalMB,
+		SwapUsedMB:  256,
+		SwapTotalMB: 2048,
+		Load:        load,
+		Load5:       load * 0.9,
+		Load15:      load * 0.8,
+		NetKB:       demoNet(int64(n)),
+		// Grows steadily by 2MiB per sample.
+		NetTotalBytes: n * 2 * 1024 * 1024,
+		OkLoad:        true,
+		OkCPU:         true,
+		OkCPUDetail:   true,
+		OkMem:         true,
+		OkNet:         true,
+		OkNetTotal:    true,
+	}
+}
+
+// demoSystem fakes the info row to match demoMetrics.
+func demoSystem(formatRate func(float64) string) SystemInfo {
+	return SystemInfo{
+		Uptime: "UPTIME: 3d 4h",
+		Cores:  "CPU: 8 cores (4 physical)",
+		Disk:   "DISK: / 100G used 42G (42%)",
+		Net:    "NET: eth0 " + formatRate(demoNet(demoStep.Load())),
+		OS:     "OS: Demo Linux 1.0",
+	}
+}
+
+// demoNet scatters the rate across 0-3.8MiB/s by hashing the step.
+func demoNet(step int64) float64 {
+	return float64(step*7919%97) * 40
+}
+
+// demoProcs is the fixed process list the demo overview draws from.
+var demoProcs = []Process{
+	{PID: 812, CPU: 23.5, Mem: 4.1, Command: "postgres"},
+	{PID: 1290, CPU: 12.0, Mem: 2.6, Command: "nginx"},
+	{PID: 4411, CPU: 6.2, Mem: 8.3, Command: "java"},
+	{PID: 77, CPU: 1.4, Mem: 0.3, Command: "sshd"},
+	{PID: 1, CPU: 0.1, Mem: 0.2, Command: "systemd"},
+}
+
+// demoOverview fakes every requested mount at the same 42% as demoSystem.
+func demoOverview(mounts []string, top int) OverviewSample {
+	var sample OverviewSample
+	for _, mount := range mounts {
+		sample.Disks = append(sample.Disks, DiskUsage{Mount: mount, SizeKB: 100 << 20, UsedKB: 42 << 20, Pct: 42})
+	}
+	sample.ProcCount = len(demoProcs)
+	sample.Procs = topProcesses(append([]Process(nil), demoProcs...), top)
+	return sample
+}
@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type doctorProbe struct {
+	source string
+	metric string
+	hint   string
+}
+
+// doctorProbes lists every external source the samplers read. Sources
+// starting with "/" are files; the rest are looked up on PATH.
+var doctorProbes = []doctorProbe{
+	{"/proc/loadavg", "load", "Only available on Linux."},
+	{"uptime", "load", "Install procps or coreutils."},
+	{"/proc/stat", "cpu", "Only available on Linux."},
+	{"vmstat", "cpu", "Install procps."},
+	{"mpstat", "cpu", "Install sysstat."},
+	{"/proc/meminfo", "memory", "Only available on Linux."},
+	{"free", "memory", "Install procps."},
+	{"vm_stat", "memory", "Only available on macOS."},
+	{"df", "disk", "Install coreutils."},
+	{"/proc/net/dev", "network", "Only available on Linux."},
+	{"netstat", "network", "Install net-tools."},
+}
+
+var statPath = os.Stat
+
+// Doctor probes each metric source, writes a checklist to w and returns a
+// process exit code: 0 when every metric has at least one working source.
+func Doctor(w io.Writer) int {
+	fmt.Fprintln(w, "perfdeck doctor")
+
+	var metrics []string
+	available := make(map[string]bool)
+	for _, p := range doctorProbes {
+		if _, seen := available[p.metric]; !seen {
+			metrics = append(metrics, p.metric)
+			available[p.metric] = false
+		}
+		ok := probeAvailable(p.source)
+		if ok {
+			available[p.metric] = true
+			fmt.Fprintf(w, "  [ok]      %-14s %s\n", p.source, p.metric)
+		} else {
+			fmt.Fprintf(w, "  [missing] %-14s %s - %s\n", p.source, p.metric, p.hint)
+		}
+	}
+
+	var missing []string
+	for _, metric := range metrics {
+		if !available[metric] {
+			missing = append(missing, metric)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(w, "\nUnavailable metrics: %s\n", strings.Join(missing, ", "))
+		return 1
+	}
+	fmt.Fprintln(w, "\nAll metrics have a working source.")
+	return 0
+}
+
+// AvailableMetrics reports for each metric whether at least one of its
+// sources is present.
+func AvailableMetrics() map[string]bool {
+	available := make(map[string]bool)
+	for _, p := range doctorProbes {
+		available[p.metric] = available[p.metric] || probeAvailable(p.source)
+	}
+	return available
+}
+
+func probeAvailable(source string) bool {
+	if strings.HasPrefix(source, "/") {
+		_, err := statPath(source)
+		return err == nil
+	}
+	_, err := lookPath(source)
+	return err == nil
+}
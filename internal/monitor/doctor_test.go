@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDoctor(t *testing.T) {
+	origLookPath, origStatPath := lookPath, statPath
+	t.Cleanup(func() { lookPath, statPath = origLookPath, origStatPath })
+
+	statPath = func(string) (os.FileInfo, error) { return nil, os.ErrNotExist }
+
+	installed := map[string]bool{"uptime": true, "vmstat": true, "free": true, "df": true, "netstat": true}
+	lookPath = func(file string) (string, error) {
+		if installed[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	var out bytes.Buffer
+	if code := Doctor(&out); code != 0 {
+		t.Errorf("Doctor() = %d, want 0 when every metric has a source\n%s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "[missing] mpstat") {
+		t.Errorf("expected mpstat reported missing, got:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "[ok]      vmstat") {
+		t.Errorf("expected vmstat reported ok, got:\n%s", out.String())
+	}
+
+	delete(installed, "netstat")
+	out.Reset()
+	if code := Doctor(&out); code != 1 {
+		t.Errorf("Doctor() = %d, want 1 with no network source", code)
+	}
+	if !strings.Contains(out.String(), "Unavailable metrics: network") {
+		t.Errorf("expected network listed as unavailable, got:\n%s", out.String())
+	}
+
+	available := AvailableMetrics()
+	for _, metric := range []string{"load", "cpu", "memory", "disk"} {
+		if !available[metric] {
+			t.Errorf("AvailableMetrics()[%q] = false, want true", metric)
+		}
+	}
+	if available["network"] {
+		t.Error("AvailableMetrics() reports network without a source")
+	}
+}
@@ -0,0 +1,221 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ExprVars are the metric names a derived expression may use: cpu and mem
+// in percent, load as the 1-minute average and net in KB/s.
+var ExprVars = []string{"cpu", "mem", "load", "net"}
+
+// ErrDivByZero is returned when an expression divides by zero.
+var ErrDivByZero = errors.New("division by zero")
+
+// Expr is a parsed arithmetic expression over metric names, supporting
+// + - * /, unary minus, parentheses and numeric constants.
+type Expr struct {
+	root exprNode
+}
+
+type exprNode interface {
+	eval(vars map[string]float64) (float64, error)
+}
+
+type numNode float64
+
+func (n numNode) eval(map[string]float64) (float64, error) { return float64(n), nil }
+
+type varNode string
+
+func (v varNode) eval(vars map[string]float64) (float64, error) {
+	x, ok := vars[string(v)]
+	if !ok {
+		return 0, fmt.Errorf("%s unavailable", v)
+	}
+	return x, nil
+}
+
+type negNode struct{ x exprNode }
+
+func (n negNode) eval(vars map[string]float64) (float64, error) {
+	x, err := n.x.eval(vars)
+	return -x, err
+}
+
+type binNode struct {
+	op   byte
+	l, r exprNode
+}
+
+func (b binNode) eval(vars map[string]float64) (float64, error) {
+	l, err := b.l.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.r.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	switch b.op {
+	case '+':
+		return l + r, nil
+	case '-':
+		return l - r, nil
+	case '*':
+		return l * r, nil
+	default:
+		if r == 0 {
+			return 0, ErrDivByZero
+		}
+		return l / r, nil
+	}
+}
+
+// Eval computes the expression with vars, failing when a metric it uses is
+// missing or it divides by zero.
+func (e *Expr) Eval(vars map[string]float64) (float64, error) {
+	return e.root.eval(vars)
+}
+
+// ExprValues maps each available metric in s to its ExprVars name.
+func ExprValues(s MetricsSample) map[string]float64 {
+	vars := make(map[string]float64, len(ExprVars))
+	if s.OkCPU {
+		vars["cpu"] = s.CPU
+	}
+	if s.OkMem {
+		vars["mem"] = s.Mem
+	}
+	if s.OkLoad {
+		vars["load"] = s.Load
+	}
+	if s.OkNet {
+		vars["net"] = s.NetKB
+	}
+	return vars
+}
+
+// ParseExpr parses src, rejecting names other than ExprVars.
+func ParseExpr(src string) (*Expr, error) {
+	p := &exprParser{src: src}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("expr %q: unexpected %q at %d", src, p.src[p.pos], p.pos)
+	}
+	return &Expr{root: root}, nil
+}
+
+type exprParser struct {
+	src string
+	pos int
+}
+
+func (p *exprParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// peek returns the next non-space byte, or 0 at the end.
+func (p *exprParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *exprParser) parseSum() (exprNode, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == '+' || op == '-'; op = p.peek() {
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = binNode{op: op, l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseProduct() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for op := p.peek(); op == '*' || op == '/'; op = p.peek() {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binNode{op: op, l: left, r: right}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.peek() == '-' {
+		p.pos++
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negNode{x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	c := p.peek()
+	switch {
+	case c == '(':
+		p.pos++
+		x, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("expr %q: missing ')'", p.src)
+		}
+		p.pos++
+		return x, nil
+	case c == '.' || (c >= '0' && c <= '9'):
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] == '.' || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+			p.pos++
+		}
+		v, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			return nil, fmt.Errorf("expr %q: bad number %q", p.src, p.src[start:p.pos])
+		}
+		return numNode(v), nil
+	case unicode.IsLetter(rune(c)):
+		start := p.pos
+		for p.pos < len(p.src) && unicode.IsLetter(rune(p.src[p.pos])) {
+			p.pos++
+		}
+		name := strings.ToLower(p.src[start:p.pos])
+		for _, v := range ExprVars {
+			if v == name {
+				return varNode(name), nil
+			}
+		}
+		return nil, fmt.Errorf("expr %q: unknown metric %q (want one of %s)", p.src, name, strings.Join(ExprVars, ", "))
+	case c == 0:
+		return nil, fmt.Errorf("expr %q: unexpected end", p.src)
+	default:
+		return nil, fmt.Errorf("expr %q: unexpected %q at %d", p.src, c, p.pos)
+	}
+}
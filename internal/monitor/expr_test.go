@@ -0,0 +1,61 @@
+package monitor
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExprEval(t *testing.T) {
+	vars := map[string]float64{"cpu": 40, "mem": 60, "load": 2, "net": 1024}
+	tests := []struct {
+		src  string
+		want float64
+	}{
+		{"cpu", 40},
+		{"(cpu + mem) / 2", 50},
+		{"cpu + mem / 2", 70},
+		{"2 + 3 * 4", 14},
+		{"(2 + 3) * 4", 20},
+		{"10 - 4 - 3", 3},
+		{"100 / 10 / 5", 2},
+		{"-load * 2", -4},
+		{"--load", 2},
+		{"net / 1024", 1},
+		{"0.5 * CPU", 20},
+	}
+	for _, tt := range tests {
+		e, err := ParseExpr(tt.src)
+		if err != nil {
+			t.Errorf("ParseExpr(%q): %v", tt.src, err)
+			continue
+		}
+		if got, err := e.Eval(vars); err != nil || got != tt.want {
+			t.Errorf("Eval(%q) = %v, %v; want %v", tt.src, got, err, tt.want)
+		}
+	}
+}
+
+func TestExprDivByZero(t *testing.T) {
+	for _, src := range []string{"cpu / 0", "mem / (load - 2)"} {
+		e, err := ParseExpr(src)
+		if err != nil {
+			t.Fatalf("ParseExpr(%q): %v", src, err)
+		}
+		if _, err := e.Eval(map[string]float64{"cpu": 1, "mem": 1, "load": 2}); !errors.Is(err, ErrDivByZero) {
+			t.Errorf("Eval(%q) error = %v, want ErrDivByZero", src, err)
+		}
+	}
+}
+
+func TestExprErrors(t *testing.T) {
+	for _, src := range []string{"", "cpu +", "(cpu", "cpu)", "disk * 2", "cpu $ 2", "1..2"} {
+		if _, err := ParseExpr(src); err == nil {
+			t.Errorf("ParseExpr(%q) should fail", src)
+		}
+	}
+
+	e, _ := ParseExpr("cpu + net")
+	if _, err := e.Eval(ExprValues(MetricsSample{CPU: 10, OkCPU: true})); err == nil {
+		t.Error("Eval should fail when a metric is unavailable")
+	}
+}
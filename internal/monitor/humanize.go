@@ -0,0 +1,77 @@
+package monitor
+
+import (
+	"math"
+	"strconv"
+)
+
+// unitScale is a ladder of units, each base times the one before, and the
+// number of decimals each is shown with.
+type unitScale struct {
+	base     float64
+	units    []string
+	decimals []int
+}
+
+var (
+	// byteScale shows whole bytes and KiB, then one decimal from MiB up.
+	byteScale = unitScale{
+		base:     1024,
+		units:    []string{"B", "KiB", "MiB", "GiB", "TiB"},
+		decimals: []int{0, 0, 1, 1, 1},
+	}
+	// bitScale starts at Kbps, in network-style decimal units.
+	bitScale = unitScale{
+		base:     1000,
+		units:    []string{"Kbps", "Mbps"},
+		decimals: []int{0, 1},
+	}
+	// gibScale is a single bare unit for amounts already in GiB.
+	gibScale = unitScale{units: []string{""}, decimals: []int{1}}
+	// countScale uses decimal suffixes for counts.
+	countScale = unitScale{
+		base:     1000,
+		units:    []string{"", "k", "M", "G"},
+		decimals: []int{0, 1, 1, 1},
+	}
+)
+
+// format renders n in the largest unit it reaches. The unit is chosen after
+// rounding to the decimals shown, so 1023.6 bytes reads "1KiB" rather than
+// "1024B" and 1048575 bytes reads "1.0MiB" rather than "1024KiB".
+func (s unitScale) format(n float64) string {
+	unit := 0
+	for unit < len(s.units)-1 && roundTo(math.Abs(n), s.decimals[unit]) >= s.base {
+		n /= s.base
+		unit++
+	}
+	return strconv.FormatFloat(n, 'f', s.decimals[unit], 64) + s.units[unit]
+}
+
+// roundTo rounds n to the given number of decimals.
+func roundTo(n float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(n*p) / p
+}
+
+// humanizeBytes renders a byte count in binary units: whole bytes and KiB,
+// then one decimal from MiB up, e.g. "512B", "1023KiB", "1.5MiB".
+func humanizeBytes(n float64) string {
+	return byteScale.format(n)
+}
+
+// FormatBytes renders a byte count in binary units, e.g. "1.2GiB".
+func FormatBytes(n float64) string {
+	return humanizeBytes(n)
+}
+
+// humanizeCount renders a count with a decimal suffix, e.g. "999", "1.2k",
+// "3.4M".
+func humanizeCount(n int) string {
+	return countScale.format(float64(n))
+}
+
+// FormatCount renders a count with a decimal suffix, e.g. "1.2k".
+func FormatCount(n int) string {
+	return humanizeCount(n)
+}
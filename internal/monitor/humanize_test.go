@@ -0,0 +1,46 @@
+package monitor
+
+import "testing"
+
+func TestHumanizeBytes(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected string
+	}{
+		{0, "0B"},
+		{1023, "1023B"},
+		{1024, "1KiB"},
+		{1023.6, "1KiB"},
+		{1048575, "1.0MiB"},
+		{1048576, "1.0MiB"},
+		{1572864, "1.5MiB"},
+		{1073741824, "1.0GiB"},
+		{1 << 50, "1024.0TiB"},
+	}
+	for _, tt := range tests {
+		if got := humanizeBytes(tt.input); got != tt.expected {
+			t.Errorf("humanizeBytes(%v) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestHumanizeCount(t *testing.T) {
+	tests := []struct {
+		input    int
+		expected string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1.0k"},
+		{1234, "1.2k"},
+		{999950, "1.0M"},
+		{3400000, "3.4M"},
+		{2000000000, "2.0G"},
+		{-1500, "-1.5k"},
+	}
+	for _, tt := range tests {
+		if got := humanizeCount(tt.input); got != tt.expected {
+			t.Errorf("humanizeCount(%d) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
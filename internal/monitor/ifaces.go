@@ -0,0 +1,159 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IfaceRate is the receive and transmit rate of one interface in KB/s.
+// Ok is false until two readings exist or when the interface is missing.
+type IfaceRate struct {
+	Name string
+	RxKB float64
+	TxKB float64
+	Ok   bool
+}
+
+var (
+	ifaceMu     sync.Mutex
+	ifacePrevAt time.Time
+	ifacePrevRx map[string]uint64
+	ifacePrevTx map[string]uint64
+)
+
+// IfaceRates measures the named interfaces against the previous call.
+func IfaceRates(ctx context.Context, names []string) []IfaceRate {
+	rx, tx, ok := readIfaceBytes(ctx)
+
+	ifaceMu.Lock()
+	defer ifaceMu.Unlock()
+	now := time.Now()
+	secs := now.Sub(ifacePrevAt).Seconds()
+	rates := make([]IfaceRate, len(names))
+	for i, name := range names {
+		rates[i].Name = name
+		if !ok || ifacePrevAt.IsZero() || secs <= 0 {
+			continue
+		}
+		curRx, hasRx := rx[name]
+		curTx, hasTx := tx[name]
+		prevRx, hadRx := ifacePrevRx[name]
+		prevTx, hadTx := ifacePrevTx[name]
+		if !hasRx || !hasTx || !hadRx || !hadTx || curRx < prevRx || curTx < prevTx {
+			continue
+		}
+		rates[i].RxKB = float64(curRx-prevRx) / 1024.0 / secs
+		rates[i].TxKB = float64(curTx-prevTx) / 1024.0 / secs
+		rates[i].Ok = true
+	}
+	if ok {
+		ifacePrevAt, ifacePrevRx, ifacePrevTx = now, rx, tx
+	}
+	return rates
+}
+
+// FormatIfaceRates renders rates side by side, e.g.
+// "eth0 rx 12KiB/s tx 3KiB/s   wlan0 -".
+func FormatIfaceRates(rates []IfaceRate, formatRate func(float64) string) string {
+	parts := make([]string, len(rates))
+	for i, r := range rates {
+		if !r.Ok {
+			parts[i] = r.Name + " -"
+			continue
+		}
+		parts[i] = fmt.Sprintf("%s rx %s tx %s", r.Name, formatRate(r.RxKB), formatRate(r.TxKB))
+	}
+	return strings.Join(parts, "   ")
+}
+
+func resetIfaceBaseline() {
+	ifaceMu.Lock()
+	defer ifaceMu.Unlock()
+	ifacePrevAt, ifacePrevRx, ifacePrevTx = time.Time{}, nil, nil
+}
+
+func readIfaceBytes(ctx context.Context) (rx, tx map[string]uint64, ok bool) {
+	if data, err := readFile("/proc/net/dev"); err == nil {
+		rx, tx = parseNetDevIfaces(data)
+		return rx, tx, len(rx) > 0
+	}
+	if _, err := lookPath("netstat"); err != nil {
+		return nil, nil, false
+	}
+	out, err := runQuickCmd(ctx, []string{"netstat", "-ib"}, 2*time.Second)
+	if err != nil {
+		return nil, nil, false
+	}
+	rx, tx = parseNetstatIfaces(out)
+	return rx, tx, len(rx) > 0
+}
+
+// parseNetDevIfaces returns per-interface received and transmitted byte
+// totals from /proc/net/dev.
+func parseNetDevIfaces(data []byte) (rx, tx map[string]uint64) {
+	rx, tx = make(map[string]uint64), make(map[string]uint64)
+	for _, line := range strings.Split(string(data), "\n") {
+		name, counters, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 16 {
+			continue
+		}
+		r, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		t, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		rx[name], tx[name] = r, t
+	}
+	return rx, tx
+}
+
+// parseNetstatIfaces returns per-interface byte totals from netstat -ib.
+// Interfaces are listed once per address with the same counters, so only
+// the first row of each is used.
+func parseNetstatIfaces(out string) (rx, tx map[string]uint64) {
+	rx, tx = make(map[string]uint64), make(map[string]uint64)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return rx, tx
+	}
+	header := strings.Fields(lines[0])
+	iIdx := indexOf(header, "Ibytes")
+	oIdx := indexOf(header, "Obytes")
+	nIdx := indexOf(header, "Name")
+	if iIdx == -1 || oIdx == -1 || nIdx == -1 {
+		return rx, tx
+	}
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) != len(header) {
+			// Rows without an address column don't line up with the header.
+			continue
+		}
+		name := fields[nIdx]
+		if _, seen := rx[name]; seen {
+			continue
+		}
+		ib, err := strconv.ParseUint(fields[iIdx], 10, 64)
+		if err != nil {
+			continue
+		}
+		ob, err := strconv.ParseUint(fields[oIdx], 10, 64)
+		if err != nil {
+			continue
+		}
+		rx[name], tx[name] = ib, ob
+	}
+	return rx, tx
+}
@@ -0,0 +1,63 @@
+package monitor
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseNetDevIfaces(t *testing.T) {
+	data := []byte(`Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo:  123456     100    0    0    0     0          0         0   123456     100    0    0    0     0       0          0
+  eth0: 9876543    5000    0    0    0     0          0        12  1234567    4000    0    0    0     0       0          0
+ wlan0:    2048      10    0    0    0     0          0         0     1024       8    0    0    0     0       0          0
+`)
+	rx, tx := parseNetDevIfaces(data)
+	wantRx := map[string]uint64{"lo": 123456, "eth0": 9876543, "wlan0": 2048}
+	wantTx := map[string]uint64{"lo": 123456, "eth0": 1234567, "wlan0": 1024}
+	if !reflect.DeepEqual(rx, wantRx) {
+		t.Errorf("rx = %v, want %v", rx, wantRx)
+	}
+	if !reflect.DeepEqual(tx, wantTx) {
+		t.Errorf("tx = %v, want %v", tx, wantTx)
+	}
+}
+
+func TestReadIfaceBytesUsesProcNetDev(t *testing.T) {
+	origReadFile := readFile
+	t.Cleanup(func() { readFile = origReadFile })
+	readFile = func(path string) ([]byte, error) {
+		if path != "/proc/net/dev" {
+			t.Errorf("read %q, want /proc/net/dev", path)
+		}
+		return []byte("Inter-| header\n face | header\n  eth0: 100 1 0 0 0 0 0 0 200 2 0 0 0 0 0 0\n"), nil
+	}
+	rx, tx, ok := readIfaceBytes(context.Background())
+	if !ok || rx["eth0"] != 100 || tx["eth0"] != 200 {
+		t.Errorf("readIfaceBytes = %v, %v, %v; want eth0 100/200", rx, tx, ok)
+	}
+}
+
+func TestParseNetstatIfaces(t *testing.T) {
+	out := `Name       Mtu   Network       Address            Ipkts Ierrs     Ibytes    Opkts Oerrs     Obytes  Coll
+lo0        16384 <Link#1>                          1000     0     500000     1000     0     500000     0
+en0        1500  <Link#4>    a4:83:e7:00:00:01    20000     0    9000000    15000     0    3000000     0
+en0        1500  192.168.1     192.168.1.20       20000     -    9000000    15000     -    3000000     -
+`
+	rx, tx := parseNetstatIfaces(out)
+	if rx["en0"] != 9000000 || tx["en0"] != 3000000 {
+		t.Errorf("en0 = %d/%d, want 9000000/3000000", rx["en0"], tx["en0"])
+	}
+}
+
+func TestFormatIfaceRates(t *testing.T) {
+	rates := []IfaceRate{
+		{Name: "eth0", RxKB: 12, TxKB: 3, Ok: true},
+		{Name: "wlan0"},
+	}
+	want := "eth0 rx 12KiB/s tx 3KiB/s   wlan0 -"
+	if got := FormatIfaceRates(rates, FormatRate); got != want {
+		t.Errorf("FormatIfaceRates = %q, want %q", got, want)
+	}
+}
@@ -4,22 +4,54 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"log"
+	"math"
 	"os"
 	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type MetricsSample struct {
-	Load   float64
-	CPU    float64
-	Mem    float64
-	NetKB  float64
-	OkLoad bool
-	OkCPU  bool
-	OkMem  bool
-	OkNet  bool
+	Load  float64
+	CPU   float64
+	Mem   float64
+	NetKB float64
+	// Load5 and Load15 complete the load triple alongside Load (1 minute).
+	Load5  float64
+	Load15 float64
+	// MemUsedMB and MemTotalMB are zero when the source only reports a
+	// percentage (vm_stat). The same goes for swap, which is also zero when
+	// no swap is configured.
+	MemUsedMB   float64
+	MemTotalMB  float64
+	SwapUsedMB  float64
+	SwapTotalMB float64
+	// IOWait and Steal are the share of CPU time spent waiting on I/O and
+	// taken by the hypervisor. OkCPUDetail is false when the CPU source
+	// only reports a busy percentage.
+	IOWait float64
+	Steal  float64
+	// NetTotalBytes is the traffic since the first sample.
+	NetTotalBytes float64
+	OkLoad        bool
+	OkCPU         bool
+	OkCPUDetail   bool
+	OkMem         bool
+	OkNet         bool
+	OkNetTotal    bool
+	// LoadReason, CPUReason, MemReason and NetReason explain a false Ok
+	// flag, e.g. "vmstat not found" or "free: parse error".
+	LoadReason string
+	CPUReason  string
+	MemReason  string
+	NetReason  string
 }
 
 type MetricHistory struct {
@@ -31,8 +63,16 @@ type MetricHistory struct {
 
 type SystemInfo struct {
 	Uptime string
-	Disk   string
-	Net    string
+	// UptimeDetail is the full uptime with the boot time, e.g.
+	// "UPTIME: up 3 days, 4:12 (since 2024-05-01 10:02)"; empty when the
+	// uptime could not be measured.
+	UptimeDetail string
+	// Cores is the CPU count, e.g. "CPU: 8 cores (4 physical)", for
+	// reading load averages and CPU percentages in context.
+	Cores string
+	Disk  string
+	Net   string
+	OS    string
 }
 
 const (
@@ -42,24 +82,79 @@ const (
 	lo0Str        = "lo0"
 )
 
-func UpdateHistory(history MetricHistory, sample MetricsSample) MetricHistory {
-	if sample.OkLoad {
-		history.Load = append(history.Load, sample.Load)
-		history.Load = trimHistory(history.Load, HistoryLength)
-	}
-	if sample.OkCPU {
-		history.CPU = append(history.CPU, sample.CPU)
-		history.CPU = trimHistory(history.CPU, HistoryLength)
+// GapMode says what UpdateHistory records for a metric missing from a
+// sample.
+type GapMode int
+
+const (
+	// GapSkip records nothing, so the sparkline pauses.
+	GapSkip GapMode = iota
+	// GapCarry repeats the last value, keeping the trend length steady.
+	GapCarry
+	// GapMark records a gap, which sparklines draw as a gap rune. Gaps are
+	// NaN; use IsGap and Latest rather than reading values directly.
+	GapMark
+)
+
+func UpdateHistory(history MetricHistory, sample MetricsSample, mode GapMode) MetricHistory {
+	history.Load = updateSeries(history.Load, sample.Load, sample.OkLoad, mode)
+	history.CPU = updateSeries(history.CPU, sample.CPU, sample.OkCPU, mode)
+	history.Mem = updateSeries(history.Mem, sample.Mem, sample.OkMem, mode)
+	history.Net = updateSeries(history.Net, sample.NetKB, sample.OkNet, mode)
+	return history
+}
+
+// updateSeries appends v to values when ok, and otherwise what mode asks
+// for. A series with no values yet stays empty until a real sample.
+func updateSeries(values []float64, v float64, ok bool, mode GapMode) []float64 {
+	if !ok {
+		last, found := Latest(values)
+		switch {
+		case !found || mode == GapSkip:
+			return values
+		case mode == GapCarry:
+			v = last
+		default:
+			v = math.NaN()
+		}
 	}
-	if sample.OkMem {
-		history.Mem = append(history.Mem, sample.Mem)
-		history.Mem = trimHistory(history.Mem, HistoryLength)
+	return AppendHistory(values, v)
+}
+
+// IsGap reports whether v marks a missing sample.
+func IsGap(v float64) bool {
+	return math.IsNaN(v)
+}
+
+// Latest returns the newest value in a history that is not a gap.
+func Latest(values []float64) (float64, bool) {
+	for i := len(values) - 1; i >= 0; i-- {
+		if !IsGap(values[i]) {
+			return values[i], true
+		}
 	}
-	if sample.OkNet {
-		history.Net = append(history.Net, sample.NetKB)
-		history.Net = trimHistory(history.Net, HistoryLength)
+	return 0, false
+}
+
+// Max returns the largest value in a history, skipping gaps, or 0 when
+// there is none.
+func Max(values []float64) float64 {
+	out, found := 0.0, false
+	for _, v := range values {
+		if IsGap(v) {
+			continue
+		}
+		if !found || v > out {
+			out, found = v, true
+		}
 	}
-	return history
+	return out
+}
+
+// AppendHistory adds v to a metric history, keeping the last
+// HistoryLength values.
+func AppendHistory(values []float64, v float64) []float64 {
+	return trimHistory(append(values, v), HistoryLength)
 }
 
 func trimHistory(values []float64, maxLen int) []float64 {
@@ -69,53 +164,148 @@ func trimHistory(values []float64, maxLen int) []float64 {
 	return values[len(values)-maxLen:]
 }
 
-func SampleMetrics() MetricsSample {
-	var sample MetricsSample
-	if load, ok := getLoadAvg(); ok {
-		sample.Load = load
-		sample.OkLoad = true
-	}
-	if cpu, ok := getCPUUsage(); ok {
-		sample.CPU = cpu
-		sample.OkCPU = true
-	}
-	if mem, ok := getMemUsage(); ok {
-		sample.Mem = mem
-		sample.OkMem = true
-	}
-	if netKB, ok := getNetRateKB(); ok {
-		sample.NetKB = netKB
-		sample.OkNet = true
+// sampleBudget bounds a whole SampleMetrics or SampleSystem call so slow
+// probes can't stack their individual timeouts.
+var sampleBudget = 3 * time.Second
+
+// Command hooks, swapped out in tests.
+var (
+	lookPath = exec.LookPath
+	execCmd  = execQuick
+	readFile = os.ReadFile
+)
+
+// SampleMetrics probes load, CPU, memory and network. Cancelling parent
+// aborts any probe commands still running.
+func SampleMetrics(parent context.Context) MetricsSample {
+	if Demo {
+		return demoMetrics()
 	}
+	ctx, cancel := context.WithTimeout(parent, sampleBudget)
+	defer cancel()
+
+	var sample MetricsSample
+	var load [3]float64
+	if load, sample.OkLoad, sample.LoadReason = getLoadAvg(ctx); sample.OkLoad {
+		sample.Load, sample.Load5, sample.Load15 = load[0], load[1], load[2]
+	}
+	var cpu cpuUsage
+	if cpu, sample.OkCPU, sample.CPUReason = getCPUUsage(ctx); sample.OkCPU {
+		sample.CPU = cpu.busy
+		sample.IOWait, sample.Steal = cpu.iowait, cpu.steal
+		sample.OkCPUDetail = cpu.detail
+	}
+	var mem memDetail
+	if mem, sample.OkMem, sample.MemReason = getMemUsageDetail(ctx); sample.OkMem {
+		sample.Mem = (mem.usedMB / mem.totalMB) * 100
+		sample.MemUsedMB = mem.usedMB
+		sample.MemTotalMB = mem.totalMB
+		sample.SwapUsedMB = mem.swapUsedMB
+		sample.SwapTotalMB = mem.swapTotalMB
+	} else if _, err := lookPath("vm_stat"); err == nil {
+		sample.Mem, sample.OkMem, sample.MemReason = memFromVmStat(ctx)
+	}
+	sample.NetKB, sample.OkNet, sample.NetReason = getNetRateKB(ctx)
+	if total, ok := netTotalSinceStart(); ok {
+		sample.NetTotalBytes = float64(total)
+		sample.OkNetTotal = true
+	}
+	log.Printf("sample: load=%t cpu=%t mem=%t net=%t", sample.OkLoad, sample.OkCPU, sample.OkMem, sample.OkNet)
 	return sample
 }
 
-func SampleSystem() SystemInfo {
+// SampleSystem gathers the info row, rendering the network rate with
+// formatRate (FormatRate or FormatRateBits). When ifaces names interfaces,
+// the net entry shows their rx/tx rates side by side instead of the total;
+// "default" stands for the default-route interface.
+func SampleSystem(parent context.Context, formatRate func(float64) string, ifaces []string) SystemInfo {
+	if Demo {
+		return demoSystem(formatRate)
+	}
+	ctx, cancel := context.WithTimeout(parent, sampleBudget)
+	defer cancel()
+
 	var info SystemInfo
-	info.Uptime = "UPTIME: " + getUptimeShort()
+	info.Uptime = "UPTIME: " + getUptimeShort(ctx)
+	if d, ok := getUptimeDuration(ctx); ok {
+		info.UptimeDetail = "UPTIME: " + uptimeDetail(d, time.Now())
+	}
 
-	if disk := getDiskSummary(); disk != "" {
+	info.Cores = "CPU: " + formatCores(getCoreCounts())
+
+	if disk := getDiskSummary(ctx); disk != "" {
 		info.Disk = "DISK: " + disk
 	}
-	if net := getNetSummary(); net != "" {
+	if len(ifaces) > 0 {
+		info.Net = "NET: " + FormatIfaceRates(IfaceRates(ctx, resolveIfaces(ctx, ifaces)), formatRate)
+	} else if net := getNetSummary(ctx, formatRate); net != "" {
 		info.Net = "NET: " + net
 	}
+	if osName := getOSVersion(); osName != "" {
+		info.OS = "OS: " + osName
+	}
 	return info
 }
 
+// SystemSummaryText renders a host overview for the built-in about tab.
+// It relies on Go's runtime and the same probes as SampleSystem, so it
+// still produces output when optional tools are missing. Cancelling parent
+// stops the probes.
+func SystemSummaryText(parent context.Context) string {
+	ctx, cancel := context.WithTimeout(parent, sampleBudget)
+	defer cancel()
+
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = unknownStr
+	}
+	rows := [][2]string{
+		{"Host", host},
+		{"Platform", runtime.GOOS + "/" + runtime.GOARCH},
+		{"CPUs", formatCores(getCoreCounts())},
+		{"OS", getOSVersion()},
+		{"Kernel", getKernel(ctx)},
+		{"Uptime", getUptimeShort(ctx)},
+	}
+	if d, ok := getUptimeDuration(ctx); ok {
+		rows = append(rows, [2]string{"Booted", bootTime(d, time.Now()).Format(bootTimeLayout)})
+	}
+	if disk := getDiskSummary(ctx); disk != "" {
+		rows = append(rows, [2]string{"Disk", disk})
+	}
+	if net := getNetSummary(ctx, FormatRate); net != "" {
+		rows = append(rows, [2]string{"Net", net})
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		fmt.Fprintf(&b, "%-10s %s\n", row[0]+":", row[1])
+	}
+	return b.String()
+}
+
 // Internal helper helpers
 
-func runQuickCmd(cmd []string, timeout time.Duration) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+// runQuickCmd runs cmd bounded by timeout and by the caller's deadline,
+// whichever comes first.
+func runQuickCmd(ctx context.Context, cmd []string, timeout time.Duration) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
+	out, err := execCmd(ctx, cmd)
+	if err != nil {
+		log.Printf("sample: %q failed: %v", cmd, err)
+		return "", err
+	}
+	return out, nil
+}
+
+func execQuick(ctx context.Context, cmd []string) (string, error) {
 	c := exec.CommandContext(ctx, cmd[0], cmd[1:]...)
 	var out bytes.Buffer
 	c.Stdout = &out
 	c.Stderr = &out
-	if err := c.Run(); err != nil {
-		return "", err
-	}
-	return out.String(), nil
+	err := c.Run()
+	return out.String(), err
 }
 
 func parseFloat(s string) (float64, error) {
@@ -131,20 +321,114 @@ func indexOf(fields []string, target string) int {
 	return -1
 }
 
+// FormatRate renders a KB/s rate with binary units, e.g. "512KiB/s" or
+// "1.5MiB/s".
 func FormatRate(kbPerSec float64) string {
-	if kbPerSec < 1024 {
-		return fmt.Sprintf("%0.0fKB/s", kbPerSec)
-	}
-	return fmt.Sprintf("%0.1fMB/s", kbPerSec/1024.0)
+	return humanizeBytes(kbPerSec*1024) + "/s"
+}
+
+// FormatGB renders a megabyte amount as gigabytes with one decimal.
+func FormatGB(mb float64) string {
+	return gibScale.format(mb / 1024)
+}
+
+// FormatRateBits renders a KB/s rate in network-style decimal bits per second.
+// The unit is chosen after rounding, so 999.6Kbps reads "1.0Mbps" rather
+// than "1000Kbps".
+func FormatRateBits(kbPerSec float64) string {
+	return bitScale.format(kbPerSec * 1024 * 8 / 1000)
 }
 
 // System logic
 
-func getUptimeShort() string {
-	if _, err := exec.LookPath("uptime"); err != nil {
+var (
+	osOnce    sync.Once
+	osVersion string
+)
+
+// getOSVersion returns the distribution and kernel release, e.g.
+// "Ubuntu 22.04.4 LTS (6.5.0-41-generic)". It never changes while we run,
+// so it is probed once.
+func getOSVersion() string {
+	osOnce.Do(func() {
+		// Use a fresh context: the result is cached for the process
+		// lifetime and shouldn't be lost to an exhausted sample budget.
+		ctx := context.Background()
+		var name string
+		if data, err := os.ReadFile("/etc/os-release"); err == nil {
+			name = parseOSRelease(data)
+		} else if _, err := lookPath("sw_vers"); err == nil {
+			if out, err := runQuickCmd(ctx, []string{"sw_vers"}, 2*time.Second); err == nil {
+				name = parseSwVers(out)
+			}
+		}
+		var kernel string
+		if _, err := lookPath("uname"); err == nil {
+			if out, err := runQuickCmd(ctx, []string{"uname", "-r"}, 2*time.Second); err == nil {
+				kernel = strings.TrimSpace(out)
+			}
+		}
+		switch {
+		case name != "" && kernel != "":
+			osVersion = fmt.Sprintf("%s (%s)", name, kernel)
+		case name != "":
+			osVersion = name
+		default:
+			osVersion = kernel
+		}
+	})
+	return osVersion
+}
+
+func parseOSRelease(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || key != "PRETTY_NAME" {
+			continue
+		}
+		return strings.Trim(value, `"'`)
+	}
+	return ""
+}
+
+func parseSwVers(out string) string {
+	var product, version string
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "ProductName":
+			product = strings.TrimSpace(value)
+		case "ProductVersion":
+			version = strings.TrimSpace(value)
+		}
+	}
+	return strings.TrimSpace(product + " " + version)
+}
+
+func getKernel(ctx context.Context) string {
+	if _, err := lookPath("uname"); err != nil {
+		return unknownStr
+	}
+	out, err := runQuickCmd(ctx, []string{"uname", "-a"}, 2*time.Second)
+	if err != nil {
+		return unknownStr
+	}
+	return strings.TrimSpace(out)
+}
+
+// getUptimeShort renders uptime like "3d 4h", falling back to the text of
+// uptime(1) when the duration can't be read.
+func getUptimeShort(ctx context.Context) string {
+	if d, ok := getUptimeDuration(ctx); ok {
+		return humanizeDuration(d)
+	}
+	if _, err := lookPath("uptime"); err != nil {
 		return unknownStr
 	}
-	out, err := runQuickCmd([]string{"uptime"}, 2*time.Second)
+	out, err := runQuickCmd(ctx, []string{"uptime"}, 2*time.Second)
 	if err != nil {
 		return unknownStr
 	}
@@ -166,11 +450,115 @@ func getUptimeShort() string {
 	return strings.Trim(part, " ,")
 }
 
-func getDiskSummary() string {
-	if _, err := exec.LookPath("df"); err != nil {
+// getUptimeDuration returns how long the system has been up, from
+// /proc/uptime on Linux or the kern.boottime sysctl on macOS.
+func getUptimeDuration(ctx context.Context) (time.Duration, bool) {
+	if data, err := os.ReadFile("/proc/uptime"); err == nil {
+		return parseProcUptime(data)
+	}
+	if _, err := lookPath("sysctl"); err != nil {
+		return 0, false
+	}
+	out, err := runQuickCmd(ctx, []string{"sysctl", "-n", "kern.boottime"}, 2*time.Second)
+	if err != nil {
+		return 0, false
+	}
+	return parseBoottime(out, time.Now())
+}
+
+// parseProcUptime reads the first field of /proc/uptime, seconds since
+// boot, e.g. "350735.47 234388.90".
+func parseProcUptime(data []byte) (time.Duration, bool) {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, false
+	}
+	secs, err := parseFloat(fields[0])
+	if err != nil || secs < 0 {
+		return 0, false
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
+
+var boottimeRe = regexp.MustCompile(`sec = (\d+)`)
+
+// parseBoottime reads kern.boottime output, e.g.
+// "{ sec = 1700000000, usec = 0 } Tue Nov 14 22:13:20 2023", and returns
+// the time elapsed since then.
+func parseBoottime(out string, now time.Time) (time.Duration, bool) {
+	m := boottimeRe.FindStringSubmatch(out)
+	if m == nil {
+		return 0, false
+	}
+	secs, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	d := now.Sub(time.Unix(secs, 0))
+	if d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// bootTimeLayout formats the boot time in the detailed uptime.
+const bootTimeLayout = "2006-01-02 15:04"
+
+// bootTime is when the system booted, given its uptime at now.
+func bootTime(uptime time.Duration, now time.Time) time.Time {
+	return now.Add(-uptime).Truncate(time.Second)
+}
+
+// formatUptimeLong renders uptime the way uptime(1) does, e.g.
+// "3 days, 4:12", "1 day, 0:05", "4:12" or "12 min".
+func formatUptimeLong(d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	mins := int(d/time.Minute) % 60
+	clock := fmt.Sprintf("%d:%02d", hours, mins)
+	if days == 0 && hours == 0 {
+		clock = fmt.Sprintf("%d min", mins)
+	}
+	switch days {
+	case 0:
+		return clock
+	case 1:
+		return "1 day, " + clock
+	default:
+		return fmt.Sprintf("%d days, %s", days, clock)
+	}
+}
+
+// uptimeDetail renders the full uptime with the boot time, e.g.
+// "up 3 days, 4:12 (since 2024-05-01 10:02)".
+func uptimeDetail(uptime time.Duration, now time.Time) string {
+	return fmt.Sprintf("up %s (since %s)", formatUptimeLong(uptime), bootTime(uptime, now).Format(bootTimeLayout))
+}
+
+// humanizeDuration renders d with its two most significant units, e.g.
+// "3d 4h", "4h 12m" or "12m".
+func humanizeDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	}
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	mins := int(d/time.Minute) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	default:
+		return fmt.Sprintf("%dm", mins)
+	}
+}
+
+func getDiskSummary(ctx context.Context) string {
+	if _, err := lookPath("df"); err != nil {
 		return ""
 	}
-	out, err := runQuickCmd([]string{"df", "-h", "/"}, 2*time.Second)
+	out, err := runQuickCmd(ctx, []string{"df", "-h", "/"}, 2*time.Second)
 	if err != nil {
 		return ""
 	}
@@ -188,26 +576,31 @@ func getDiskSummary() string {
 	return fmt.Sprintf("/ %s used %s (%s)", size, used, usePct)
 }
 
-func getNetSummary() string {
-	rate, ok := getNetRateKB()
+func getNetSummary(ctx context.Context, formatRate func(float64) string) string {
+	rate, ok := peekNetRateKB(ctx)
 	if !ok {
 		return ""
 	}
-	iface := getPrimaryIface()
+	iface := getPrimaryIface(ctx)
 	if iface == "" {
 		iface = "iface"
 	}
-	return fmt.Sprintf("%s %s", iface, FormatRate(rate))
+	return fmt.Sprintf("%s %s", iface, formatRate(rate))
 }
 
-func getPrimaryIface() string {
+// getPrimaryIface names the interface for the net summary: the one the
+// default route goes through, else the first non-loopback interface.
+func getPrimaryIface(ctx context.Context) string {
+	if iface := defaultRouteIface(ctx); iface != "" {
+		return iface
+	}
 	if data, err := os.ReadFile("/proc/net/dev"); err == nil {
 		if iface := firstIfaceLinux(data); iface != "" {
 			return iface
 		}
 	}
-	if _, err := exec.LookPath("netstat"); err == nil {
-		if iface := firstIfaceDarwin(); iface != "" {
+	if _, err := lookPath("netstat"); err == nil {
+		if iface := firstIfaceDarwin(ctx); iface != "" {
 			return iface
 		}
 	}
@@ -233,8 +626,8 @@ func firstIfaceLinux(data []byte) string {
 	return ""
 }
 
-func firstIfaceDarwin() string {
-	out, err := runQuickCmd([]string{"netstat", "-ib"}, 2*time.Second)
+func firstIfaceDarwin(ctx context.Context) string {
+	out, err := runQuickCmd(ctx, []string{"netstat", "-ib"}, 2*time.Second)
 	if err != nil {
 		return ""
 	}
@@ -261,63 +654,134 @@ func firstIfaceDarwin() string {
 	return ""
 }
 
-func getLoadAvg() (float64, bool) {
-	if _, err := exec.LookPath("uptime"); err != nil {
-		return 0, false
+// getLoadAvg returns the 1, 5 and 15 minute load averages, read from
+// /proc/loadavg on Linux and parsed from uptime elsewhere.
+func getLoadAvg(ctx context.Context) ([3]float64, bool, string) {
+	reason := notFound("uptime")
+	if data, err := readFile("/proc/loadavg"); err == nil {
+		if load, ok := parseProcLoadavg(data); ok {
+			return load, true, ""
+		}
+		reason = parseFailed("/proc/loadavg")
 	}
-	out, err := runQuickCmd([]string{"uptime"}, 2*time.Second)
+	if _, err := lookPath("uptime"); err != nil {
+		return [3]float64{}, false, reason
+	}
+	out, err := runQuickCmd(ctx, []string{"uptime"}, 2*time.Second)
 	if err != nil {
-		return 0, false
+		return [3]float64{}, false, sourceFailed("uptime", err)
 	}
+	load, ok := parseLoadAvg(out)
+	if !ok {
+		return load, false, parseFailed("uptime")
+	}
+	return load, true, ""
+}
+
+// parseProcLoadavg reads the first three fields of /proc/loadavg, e.g.
+// "0.52 0.58 0.59 1/389 12345".
+func parseProcLoadavg(data []byte) ([3]float64, bool) {
+	var load [3]float64
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return load, false
+	}
+	for i := range load {
+		v, err := parseFloat(fields[i])
+		if err != nil {
+			return load, false
+		}
+		load[i] = v
+	}
+	return load, true
+}
+
+// parseLoadAvg reads the load triple from uptime output. Linux separates
+// the values with commas, macOS with spaces.
+func parseLoadAvg(out string) ([3]float64, bool) {
+	var load [3]float64
 	line := strings.TrimSpace(out)
 	idx := strings.Index(line, "load average")
 	if idx == -1 {
-		idx = strings.Index(line, "load averages")
+		return load, false
 	}
-	if idx == -1 {
-		return 0, false
+	_, part, ok := strings.Cut(line[idx:], ":")
+	if !ok {
+		return load, false
 	}
-	part := line[idx:]
-	parts := strings.FieldsFunc(part, func(r rune) bool {
-		return r == ':' || r == ','
+	fields := strings.FieldsFunc(part, func(r rune) bool {
+		return r == ',' || r == ' '
 	})
-	if len(parts) < 2 {
-		return 0, false
+	if len(fields) < 3 {
+		return load, false
 	}
-	loadStr := strings.TrimSpace(parts[1])
-	loadStr = strings.TrimSuffix(loadStr, ",")
-	load, err := parseFloat(loadStr)
-	if err != nil {
-		return 0, false
+	for i := range load {
+		v, err := parseFloat(fields[i])
+		if err != nil {
+			return load, false
+		}
+		load[i] = v
 	}
 	return load, true
 }
 
-func getCPUUsage() (float64, bool) {
-	if _, err := exec.LookPath("vmstat"); err == nil {
-		if cpu, ok := cpuFromVmstat(); ok {
-			return cpu, true
+// getCPUUsage reads /proc/stat where it exists. Elsewhere vmstat or
+// mpstat measure in the background and each sample takes the latest
+// finished reading, so sampling never waits on them.
+func getCPUUsage(ctx context.Context) (cpuUsage, bool, string) {
+	cpu, ok, reason := cpuFromProcStat(ctx)
+	if ok {
+		return cpu, true, ""
+	}
+	if reason != "" {
+		// /proc/stat exists; the slow tools would not do better.
+		return cpuUsage{}, false, reason
+	}
+	return cpuFromInterval(ctx)
+}
+
+// intervalCPU tries vmstat and then mpstat, whose interval forms block for
+// a second: the forms without an interval only report averages since
+// boot. When both fail, the reason comes from the last one tried.
+func intervalCPU(ctx context.Context) (cpuUsage, bool, string) {
+	var reason string
+	if _, err := lookPath("vmstat"); err == nil {
+		busy, ok, why := cpuFromVmstat(ctx)
+		if ok {
+			return cpuUsage{busy: busy}, true, ""
 		}
+		reason = why
 	}
-	if _, err := exec.LookPath("mpstat"); err == nil {
-		if cpu, ok := cpuFromMpstat(); ok {
-			return cpu, true
+	if _, err := lookPath("mpstat"); err == nil {
+		busy, ok, why := cpuFromMpstat(ctx)
+		if ok {
+			return cpuUsage{busy: busy}, true, ""
 		}
+		reason = why
 	}
-	return 0, false
+	if reason == "" {
+		reason = notFound("vmstat and mpstat")
+	}
+	return cpuUsage{}, false, reason
 }
 
-func cpuFromVmstat() (float64, bool) {
-	// On macOS, vmstat 1 2 gives a good average.
-	// On Linux, vmstat gives it in the last line.
-	out, err := runQuickCmd([]string{"vmstat", "1", "2"}, 3*time.Second)
+// cpuFromVmstat reads the second report of "vmstat 1 2", which covers
+// the last second; the first one averages since boot.
+func cpuFromVmstat(ctx context.Context) (float64, bool, string) {
+	out, err := runQuickCmd(ctx, []string{"vmstat", "1", "2"}, 3*time.Second)
 	if err != nil {
-		// Fallback to single shot if 1 2 fails
-		out, err = runQuickCmd([]string{"vmstat"}, 2*time.Second)
-		if err != nil {
-			return 0, false
-		}
+		return 0, false, sourceFailed("vmstat", err)
+	}
+	cpu, ok := parseVmstatCPU(out)
+	if !ok {
+		return 0, false, parseFailed("vmstat")
 	}
+	return cpu, true, ""
+}
+
+// parseVmstatCPU reads busy CPU from the "id" column of the last vmstat
+// line.
+func parseVmstatCPU(out string) (float64, bool) {
 	lines := strings.Split(strings.TrimSpace(out), "\n")
 	if len(lines) < 3 {
 		return 0, false
@@ -369,11 +833,23 @@ func cpuFromVmstat() (float64, bool) {
 	return cpu, true
 }
 
-func cpuFromMpstat() (float64, bool) {
-	out, err := runQuickCmd([]string{"mpstat", "1", "1"}, 3*time.Second)
+// cpuFromMpstat runs "mpstat 1 1", the last resort: it takes a second,
+// but mpstat without an interval only reports averages since boot.
+func cpuFromMpstat(ctx context.Context) (float64, bool, string) {
+	out, err := runQuickCmd(ctx, []string{"mpstat", "1", "1"}, 3*time.Second)
 	if err != nil {
-		return 0, false
+		return 0, false, sourceFailed("mpstat", err)
+	}
+	cpu, ok := parseMpstat(out)
+	if !ok {
+		return 0, false, parseFailed("mpstat")
 	}
+	return cpu, true, ""
+}
+
+// parseMpstat reads busy CPU from the "all" row, using %idle in the last
+// column.
+func parseMpstat(out string) (float64, bool) {
 	lines := strings.Split(strings.TrimSpace(out), "\n")
 	for i := len(lines) - 1; i >= 0; i-- {
 		line := strings.TrimSpace(lines[i])
@@ -384,7 +860,9 @@ func cpuFromMpstat() (float64, bool) {
 		if len(fields) < 5 {
 			continue
 		}
-		if strings.ToLower(fields[1]) != "all" {
+		// The time column is "10:00:00" or, in 12-hour locales,
+		// "10:00:00 AM".
+		if !slices.ContainsFunc(fields[1:3], func(f string) bool { return strings.EqualFold(f, "all") }) {
 			continue
 		}
 		idleStr := fields[len(fields)-1]
@@ -404,47 +882,111 @@ func cpuFromMpstat() (float64, bool) {
 	return 0, false
 }
 
-func getMemUsage() (float64, bool) {
-	if _, err := exec.LookPath("free"); err == nil {
-		return memFromFree()
+// memDetail is memory and swap usage in MB as reported by free -m.
+type memDetail struct {
+	usedMB, totalMB         float64
+	swapUsedMB, swapTotalMB float64
+}
+
+// getMemUsageDetail returns memory and swap usage from /proc/meminfo on
+// Linux, falling back to a single free -m elsewhere.
+func getMemUsageDetail(ctx context.Context) (memDetail, bool, string) {
+	reason := notFound("free and vm_stat")
+	if data, err := readFile("/proc/meminfo"); err == nil {
+		if mem, ok := parseMeminfo(data); ok {
+			return mem, true, ""
+		}
+		reason = parseFailed("/proc/meminfo")
 	}
-	if _, err := exec.LookPath("vm_stat"); err == nil {
-		return memFromVmStat()
+	if _, err := lookPath("free"); err != nil {
+		return memDetail{}, false, reason
 	}
-	return 0, false
+	out, err := runQuickCmd(ctx, []string{"free", "-m"}, 2*time.Second)
+	if err != nil {
+		return memDetail{}, false, sourceFailed("free", err)
+	}
+	var mem memDetail
+	var ok bool
+	if mem.usedMB, mem.totalMB, ok = parseFreeMem(out); !ok {
+		return memDetail{}, false, parseFailed("free")
+	}
+	// A missing or empty swap row just leaves swap at zero.
+	mem.swapUsedMB, mem.swapTotalMB, _ = parseFreeRow(out, "Swap:")
+	return mem, true, ""
 }
 
-func memFromFree() (float64, bool) {
-	out, err := runQuickCmd([]string{"free", "-m"}, 2*time.Second)
-	if err != nil {
-		return 0, false
+// parseMeminfo reads /proc/meminfo. Used memory is MemTotal minus
+// MemAvailable, which unlike free's "used" accounts for reclaimable cache.
+func parseMeminfo(data []byte) (memDetail, bool) {
+	kb := make(map[string]float64)
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		if v, err := parseFloat(fields[0]); err == nil {
+			kb[key] = v
+		}
+	}
+	total, available := kb["MemTotal"], kb["MemAvailable"]
+	if _, ok := kb["MemAvailable"]; !ok || total == 0 {
+		return memDetail{}, false
 	}
+	return memDetail{
+		usedMB:      (total - available) / 1024,
+		totalMB:     total / 1024,
+		swapUsedMB:  (kb["SwapTotal"] - kb["SwapFree"]) / 1024,
+		swapTotalMB: kb["SwapTotal"] / 1024,
+	}, true
+}
+
+func parseFreeMem(out string) (usedMB, totalMB float64, ok bool) {
+	return parseFreeRow(out, "Mem:")
+}
+
+// parseFreeRow reads the total and used columns of the free row starting
+// with prefix.
+func parseFreeRow(out, prefix string) (usedMB, totalMB float64, ok bool) {
 	lines := strings.Split(strings.TrimSpace(out), "\n")
 	for _, line := range lines {
-		if strings.HasPrefix(line, "Mem:") {
+		if strings.HasPrefix(line, prefix) {
 			fields := strings.Fields(line)
 			if len(fields) < 3 {
-				return 0, false
+				return 0, 0, false
 			}
 			total, err := parseFloat(fields[1])
 			if err != nil || total == 0 {
-				return 0, false
+				return 0, 0, false
 			}
 			used, err := parseFloat(fields[2])
 			if err != nil {
-				return 0, false
+				return 0, 0, false
 			}
-			return (used / total) * 100, true
+			return used, total, true
 		}
 	}
-	return 0, false
+	return 0, 0, false
 }
 
-func memFromVmStat() (float64, bool) {
-	out, err := runQuickCmd([]string{"vm_stat"}, 2*time.Second)
+func memFromVmStat(ctx context.Context) (float64, bool, string) {
+	out, err := runQuickCmd(ctx, []string{"vm_stat"}, 2*time.Second)
 	if err != nil {
-		return 0, false
+		return 0, false, sourceFailed("vm_stat", err)
+	}
+	mem, ok := parseVmStat(out)
+	if !ok {
+		return 0, false, parseFailed("vm_stat")
 	}
+	return mem, true, ""
+}
+
+// parseVmStat computes used memory from vm_stat page counts as active,
+// wired and compressed pages over all pages.
+func parseVmStat(out string) (float64, bool) {
 	lines := strings.Split(out, "\n")
 	var free, active, inactive, wired, compressed float64
 
@@ -479,55 +1021,151 @@ func memFromVmStat() (float64, bool) {
 	return (used / total) * 100, true
 }
 
-var netPrevTotal uint64
-var netPrevAt time.Time
+var (
+	netMu        sync.Mutex
+	netPrevTotal uint64
+	netPrevAt    time.Time
+	// netStartTotal is the first counter reading, the baseline for the
+	// bytes transferred since start. ResetNetBaseline leaves it alone.
+	netStartTotal uint64
+	netStartSet   bool
+)
+
+// ResetNetBaseline forgets the previous network counter reading so the
+// next sample starts a fresh rate measurement.
+func ResetNetBaseline() {
+	netMu.Lock()
+	defer netMu.Unlock()
+	netPrevTotal = 0
+	netPrevAt = time.Time{}
+	resetIfaceBaseline()
+}
 
-func getNetRateKB() (float64, bool) {
-	total, ok := readNetBytes()
+func getNetRateKB(ctx context.Context) (float64, bool, string) {
+	total, ok, reason := readNetBytes(ctx)
 	if !ok {
-		return 0, false
+		return 0, false, reason
+	}
+	netMu.Lock()
+	defer netMu.Unlock()
+	if !netStartSet || total < netStartTotal {
+		// Counters reset (e.g. an interface went away); start over.
+		netStartTotal, netStartSet = total, true
 	}
 	now := time.Now()
 	if netPrevAt.IsZero() {
 		netPrevAt = now
 		netPrevTotal = total
-		return 0, false
+		return 0, false, reasonWarming
 	}
 	if total < netPrevTotal {
 		netPrevAt = now
 		netPrevTotal = total
-		return 0, false
+		return 0, false, reasonWarming
 	}
 	secs := now.Sub(netPrevAt).Seconds()
 	if secs <= 0 {
 		netPrevAt = now
 		netPrevTotal = total
-		return 0, false
+		return 0, false, reasonWarming
 	}
 	delta := total - netPrevTotal
 	netPrevAt = now
 	netPrevTotal = total
-	return float64(delta) / 1024.0 / secs, true
+	return float64(delta) / 1024.0 / secs, true, ""
 }
 
-func readNetBytes() (uint64, bool) {
-	if data, err := os.ReadFile("/proc/net/dev"); err == nil {
-		if total, ok := sumNetBytesLinux(data); ok {
-			return total, true
-		}
+// peekNetRateKB measures the rate since the last getNetRateKB call without
+// moving its baseline, so summaries read between metrics samples don't
+// shorten the interval the metrics row measures over.
+func peekNetRateKB(ctx context.Context) (float64, bool) {
+	total, ok, _ := readNetBytes(ctx)
+	if !ok {
+		return 0, false
+	}
+	netMu.Lock()
+	prevTotal, prevAt := netPrevTotal, netPrevAt
+	netMu.Unlock()
+	if prevAt.IsZero() || total < prevTotal {
+		return 0, false
+	}
+	secs := time.Since(prevAt).Seconds()
+	if secs <= 0 {
+		return 0, false
+	}
+	return float64(total-prevTotal) / 1024.0 / secs, true
+}
+
+// netTotalSinceStart returns the bytes received and sent since the first
+// network sample.
+func netTotalSinceStart() (uint64, bool) {
+	netMu.Lock()
+	defer netMu.Unlock()
+	if !netStartSet || netPrevAt.IsZero() {
+		return 0, false
 	}
-	if _, err := exec.LookPath("netstat"); err == nil {
-		if total, ok := sumNetBytesDarwin(); ok {
-			return total, true
+	return bytesSince(netStartTotal, netPrevTotal)
+}
+
+// bytesSince computes the bytes transferred between a baseline and a later
+// counter total, reporting false when the counter went backwards.
+func bytesSince(baseline, total uint64) (uint64, bool) {
+	if total < baseline {
+		return 0, false
+	}
+	return total - baseline, true
+}
+
+// loopbackFallback counts loopback traffic when it is the only interface,
+// e.g. in a bare network namespace. See SetLoopbackFallback.
+var loopbackFallback atomic.Bool
+
+// SetLoopbackFallback makes the network rate use loopback traffic when
+// /proc/net/dev lists no other interface, instead of reporting NET as
+// unavailable.
+func SetLoopbackFallback(on bool) {
+	loopbackFallback.Store(on)
+}
+
+func readNetBytes(ctx context.Context) (uint64, bool, string) {
+	reason := notFound("netstat")
+	if data, err := readFile("/proc/net/dev"); err == nil {
+		totals := sumNetBytesLinux(data)
+		switch {
+		case totals.found:
+			return totals.total, true, ""
+		case totals.foundLoopback && loopbackFallback.Load():
+			return totals.loopback, true, ""
+		case totals.foundLoopback:
+			reason = "/proc/net/dev: " + reasonLoopbackOnly
+		default:
+			reason = parseFailed("/proc/net/dev")
 		}
 	}
-	return 0, false
+	if _, err := lookPath("netstat"); err != nil {
+		return 0, false, reason
+	}
+	out, err := runQuickCmd(ctx, []string{"netstat", "-ib"}, 2*time.Second)
+	if err != nil {
+		return 0, false, sourceFailed("netstat", err)
+	}
+	total, ok := sumNetBytesDarwin(out)
+	if !ok {
+		return 0, false, parseFailed("netstat")
+	}
+	return total, true, ""
 }
 
-func sumNetBytesLinux(data []byte) (uint64, bool) {
+// netDevTotals are the rx+tx byte counts from /proc/net/dev, with
+// loopback kept apart so "only loopback" can be told from "no data".
+type netDevTotals struct {
+	total, loopback      uint64
+	found, foundLoopback bool
+}
+
+func sumNetBytesLinux(data []byte) netDevTotals {
 	lines := strings.Split(string(data), "\n")
-	var total uint64
-	var found bool
+	var totals netDevTotals
 	for _, line := range lines {
 		if !strings.Contains(line, ":") {
 			continue
@@ -537,9 +1175,6 @@ func sumNetBytesLinux(data []byte) (uint64, bool) {
 			continue
 		}
 		iface := strings.TrimSpace(parts[0])
-		if iface == loStr || strings.HasPrefix(iface, loStr) {
-			continue
-		}
 		fields := strings.Fields(parts[1])
 		if len(fields) < 16 {
 			continue
@@ -552,17 +1187,18 @@ func sumNetBytesLinux(data []byte) (uint64, bool) {
 		if err != nil {
 			continue
 		}
-		total += rx + tx
-		found = true
+		if iface == loStr || strings.HasPrefix(iface, loStr) {
+			totals.loopback += rx + tx
+			totals.foundLoopback = true
+			continue
+		}
+		totals.total += rx + tx
+		totals.found = true
 	}
-	return total, found
+	return totals
 }
 
-func sumNetBytesDarwin() (uint64, bool) {
-	out, err := runQuickCmd([]string{"netstat", "-ib"}, 2*time.Second)
-	if err != nil {
-		return 0, false
-	}
+func sumNetBytesDarwin(out string) (uint64, bool) {
 	lines := strings.Split(strings.TrimSpace(out), "\n")
 	if len(lines) < 2 {
 		return 0, false
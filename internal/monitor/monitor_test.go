@@ -1,7 +1,11 @@
 package monitor
 
 import (
+	"context"
+	"runtime"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestFormatRate(t *testing.T) {
@@ -9,12 +13,13 @@ func TestFormatRate(t *testing.T) {
 		input    float64
 		expected string
 	}{
-		{0, "0KB/s"},
-		{500, "500KB/s"},
-		{1023, "1023KB/s"},
-		{1024, "1.0MB/s"},
-		{2048, "2.0MB/s"},
-		{1536, "1.5MB/s"},
+		{0, "0B/s"},
+		{0.5, "512B/s"},
+		{500, "500KiB/s"},
+		{1023, "1023KiB/s"},
+		{1024, "1.0MiB/s"},
+		{2048, "2.0MiB/s"},
+		{1536, "1.5MiB/s"},
 	}
 
 	for _, tt := range tests {
@@ -25,6 +30,29 @@ func TestFormatRate(t *testing.T) {
 	}
 }
 
+func TestFormatRateBits(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected string
+	}{
+		{0, "0Kbps"},
+		{1, "8Kbps"},
+		{100, "819Kbps"},
+		{122, "999Kbps"},
+		{121.99, "999Kbps"},
+		{122.02, "1.0Mbps"}, // 999.6Kbps rounds up to the next unit
+		{125, "1.0Mbps"},
+		{1280, "10.5Mbps"},
+	}
+
+	for _, tt := range tests {
+		result := FormatRateBits(tt.input)
+		if result != tt.expected {
+			t.Errorf("FormatRateBits(%v) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
 func TestTrimHistory(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -67,17 +95,308 @@ func TestUpdateHistory(t *testing.T) {
 	}
 
 	// First update
-	history = UpdateHistory(history, sample)
+	history = UpdateHistory(history, sample, GapSkip)
 	if len(history.Load) != 1 || history.Load[0] != 1.0 {
 		t.Errorf("UpdateHistory failed on first update")
 	}
 
 	// Add enough items to trigger trim (HistoryLength is 30)
 	for i := 0; i < 40; i++ {
-		history = UpdateHistory(history, sample)
+		history = UpdateHistory(history, sample, GapSkip)
 	}
 
 	if len(history.Load) != HistoryLength {
 		t.Errorf("UpdateHistory should trim to %d, got %d", HistoryLength, len(history.Load))
 	}
 }
+
+func TestUpdateHistoryGapModes(t *testing.T) {
+	ok := MetricsSample{CPU: 40, OkCPU: true}
+	missing := MetricsSample{}
+
+	var skip, carry, mark MetricHistory
+	for _, s := range []MetricsSample{missing, ok, missing, missing} {
+		skip = UpdateHistory(skip, s, GapSkip)
+		carry = UpdateHistory(carry, s, GapCarry)
+		mark = UpdateHistory(mark, s, GapMark)
+	}
+	if len(skip.CPU) != 1 {
+		t.Errorf("skip: CPU = %v, want only the real sample", skip.CPU)
+	}
+	if len(carry.CPU) != 3 || carry.CPU[1] != 40 || carry.CPU[2] != 40 {
+		t.Errorf("carry: CPU = %v, want 40 carried forward twice", carry.CPU)
+	}
+	if len(mark.CPU) != 3 || mark.CPU[0] != 40 || !IsGap(mark.CPU[1]) || !IsGap(mark.CPU[2]) {
+		t.Errorf("mark: CPU = %v, want 40 then two gaps", mark.CPU)
+	}
+	if v, found := Latest(mark.CPU); !found || v != 40 {
+		t.Errorf("Latest(%v) = %v, %t; want 40 past the gaps", mark.CPU, v, found)
+	}
+	// A series with no samples yet gets no leading gaps.
+	if len(mark.Mem) != 0 || len(carry.Mem) != 0 {
+		t.Errorf("MEM never sampled: mark %v, carry %v; want empty", mark.Mem, carry.Mem)
+	}
+}
+
+func TestSystemSummaryText(t *testing.T) {
+	text := SystemSummaryText(context.Background())
+	if text == "" {
+		t.Fatal("SystemSummaryText returned empty text")
+	}
+	if !strings.Contains(text, runtime.GOOS+"/"+runtime.GOARCH) {
+		t.Errorf("expected platform in summary, got %q", text)
+	}
+}
+
+func TestParseOSRelease(t *testing.T) {
+	data := []byte(`NAME="Ubuntu"
+VERSION="22.04.4 LTS (Jammy Jellyfish)"
+ID=ubuntu
+PRETTY_NAME="Ubuntu 22.04.4 LTS"
+VERSION_ID="22.04"
+`)
+	if got := parseOSRelease(data); got != "Ubuntu 22.04.4 LTS" {
+		t.Errorf("parseOSRelease = %q, want %q", got, "Ubuntu 22.04.4 LTS")
+	}
+	if got := parseOSRelease([]byte("NAME=Alpine\n")); got != "" {
+		t.Errorf("parseOSRelease without PRETTY_NAME = %q, want empty", got)
+	}
+}
+
+func TestParseSwVers(t *testing.T) {
+	out := "ProductName:\t\tmacOS\nProductVersion:\t\t14.4.1\nBuildVersion:\t\t23E224\n"
+	if got := parseSwVers(out); got != "macOS 14.4.1" {
+		t.Errorf("parseSwVers = %q, want %q", got, "macOS 14.4.1")
+	}
+}
+
+func TestSampleMetricsBounded(t *testing.T) {
+	origLookPath, origExecCmd, origBudget := lookPath, execCmd, sampleBudget
+	t.Cleanup(func() {
+		lookPath, execCmd, sampleBudget = origLookPath, origExecCmd, origBudget
+		ResetNetBaseline()
+	})
+
+	lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	execCmd = func(ctx context.Context, cmd []string) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	sampleBudget = 100 * time.Millisecond
+
+	start := time.Now()
+	SampleMetrics(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("SampleMetrics took %v with a slow runner, want it bounded by the budget", elapsed)
+	}
+}
+
+func TestParseFreeMem(t *testing.T) {
+	out := `               total        used        free      shared  buff/cache   available
+Mem:            7951        3402        1203         321        3345        3912
+Swap:           2047           0        2047
+`
+	used, total, ok := parseFreeMem(out)
+	if !ok {
+		t.Fatal("parseFreeMem failed")
+	}
+	if used != 3402 || total != 7951 {
+		t.Errorf("parseFreeMem = %v/%v, want 3402/7951", used, total)
+	}
+
+	if _, _, ok := parseFreeMem("Swap: 1 2 3"); ok {
+		t.Error("parseFreeMem should fail without a Mem: line")
+	}
+}
+
+func TestFormatGB(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected string
+	}{
+		{0, "0.0"},
+		{512, "0.5"},
+		{3482, "3.4"},
+		{8192, "8.0"},
+	}
+
+	for _, tt := range tests {
+		result := FormatGB(tt.input)
+		if result != tt.expected {
+			t.Errorf("FormatGB(%v) = %q, want %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestParseLoadAvg(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want [3]float64
+		ok   bool
+	}{
+		{"linux", " 10:01:02 up 3 days,  2:04,  2 users,  load average: 0.52, 0.58, 0.59", [3]float64{0.52, 0.58, 0.59}, true},
+		{"darwin", "10:01  up 3 days,  2:04, 2 users, load averages: 1.91 2.04 2.10", [3]float64{1.91, 2.04, 2.10}, true},
+		{"missing", "10:01  up 3 days", [3]float64{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseLoadAvg(tt.out)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseLoadAvg = %v, %t; want %v, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestParseProcLoadavg(t *testing.T) {
+	got, ok := parseProcLoadavg([]byte("0.52 0.58 0.59 1/389 12345\n"))
+	if !ok || got != [3]float64{0.52, 0.58, 0.59} {
+		t.Errorf("parseProcLoadavg = %v, %t; want [0.52 0.58 0.59], true", got, ok)
+	}
+	for _, bad := range []string{"", "0.52 0.58", "a b c"} {
+		if _, ok := parseProcLoadavg([]byte(bad)); ok {
+			t.Errorf("parseProcLoadavg(%q) should fail", bad)
+		}
+	}
+}
+
+func TestParseMeminfo(t *testing.T) {
+	data := []byte(`MemTotal:        8141856 kB
+MemFree:         1231872 kB
+MemAvailable:    4005888 kB
+Buffers:          215040 kB
+Cached:          3211264 kB
+SwapCached:            0 kB
+SwapTotal:       2097148 kB
+SwapFree:        1048572 kB
+`)
+	mem, ok := parseMeminfo(data)
+	if !ok {
+		t.Fatal("parseMeminfo failed")
+	}
+	if mem.totalMB != 8141856.0/1024 || mem.usedMB != (8141856.0-4005888.0)/1024 {
+		t.Errorf("parseMeminfo mem = %v/%v", mem.usedMB, mem.totalMB)
+	}
+	if mem.swapTotalMB != 2097148.0/1024 || mem.swapUsedMB != 1048576.0/1024 {
+		t.Errorf("parseMeminfo swap = %v/%v", mem.swapUsedMB, mem.swapTotalMB)
+	}
+
+	if _, ok := parseMeminfo([]byte("MemTotal: 8141856 kB\n")); ok {
+		t.Error("parseMeminfo should fail without MemAvailable")
+	}
+}
+
+func TestParseProcUptime(t *testing.T) {
+	got, ok := parseProcUptime([]byte("350735.47 234388.90\n"))
+	if !ok || got != 350735470*time.Millisecond {
+		t.Errorf("parseProcUptime = %v, %t", got, ok)
+	}
+	if _, ok := parseProcUptime([]byte("")); ok {
+		t.Error("parseProcUptime should fail on empty input")
+	}
+}
+
+func TestParseBoottime(t *testing.T) {
+	now := time.Unix(1700000000, 0).Add(26 * time.Hour)
+	got, ok := parseBoottime("{ sec = 1700000000, usec = 0 } Tue Nov 14 22:13:20 2023\n", now)
+	if !ok || got != 26*time.Hour {
+		t.Errorf("parseBoottime = %v, %t; want 26h, true", got, ok)
+	}
+	if _, ok := parseBoottime("garbage", now); ok {
+		t.Error("parseBoottime should fail without a sec field")
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{42 * time.Second, "42s"},
+		{12 * time.Minute, "12m"},
+		{4*time.Hour + 12*time.Minute, "4h 12m"},
+		{3*24*time.Hour + 4*time.Hour + 59*time.Minute, "3d 4h"},
+	}
+	for _, tt := range tests {
+		if got := humanizeDuration(tt.input); got != tt.expected {
+			t.Errorf("humanizeDuration(%v) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestDemoMode(t *testing.T) {
+	Demo = true
+	defer func() { Demo = false }()
+
+	s := SampleMetrics(context.Background())
+	if !s.OkLoad || !s.OkCPU || !s.OkMem || !s.OkNet {
+		t.Fatalf("demo sample missing metrics: %+v", s)
+	}
+	if s.CPU < 0 || s.CPU > 100 || s.Mem <= 0 || s.Mem > 100 || s.MemTotalMB == 0 {
+		t.Errorf("demo sample out of range: %+v", s)
+	}
+	if next := SampleMetrics(context.Background()); next.CPU == s.CPU {
+		t.Error("demo CPU should move between samples")
+	}
+
+	info := SampleSystem(context.Background(), FormatRate, nil)
+	for name, v := range map[string]string{"uptime": info.Uptime, "disk": info.Disk, "net": info.Net, "os": info.OS} {
+		if v == "" {
+			t.Errorf("demo system %s is empty", name)
+		}
+	}
+}
+
+func TestBytesSince(t *testing.T) {
+	tests := []struct {
+		baseline, total uint64
+		want            uint64
+		ok              bool
+	}{
+		{1000, 1000, 0, true},
+		{1000, 1300000, 1299000, true},
+		{5000, 100, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := bytesSince(tt.baseline, tt.total)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("bytesSince(%d, %d) = %d, %t; want %d, %t", tt.baseline, tt.total, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBootTime(t *testing.T) {
+	now := time.Date(2024, 5, 4, 14, 14, 30, 500, time.UTC)
+	tests := []struct {
+		uptime time.Duration
+		want   time.Time
+	}{
+		{0, time.Date(2024, 5, 4, 14, 14, 30, 0, time.UTC)},
+		{3*24*time.Hour + 4*time.Hour + 12*time.Minute, time.Date(2024, 5, 1, 10, 2, 30, 0, time.UTC)},
+		{90*time.Minute + 500*time.Millisecond, time.Date(2024, 5, 4, 12, 44, 29, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := bootTime(tt.uptime, now); !got.Equal(tt.want) {
+			t.Errorf("bootTime(%v) = %v, want %v", tt.uptime, got, tt.want)
+		}
+	}
+}
+
+func TestUptimeDetail(t *testing.T) {
+	now := time.Date(2024, 5, 4, 14, 14, 0, 0, time.UTC)
+	tests := []struct {
+		uptime time.Duration
+		want   string
+	}{
+		{12 * time.Minute, "up 12 min (since 2024-05-04 14:02)"},
+		{4*time.Hour + 12*time.Minute, "up 4:12 (since 2024-05-04 10:02)"},
+		{24*time.Hour + 5*time.Minute, "up 1 day, 0:05 (since 2024-05-03 14:09)"},
+		{3*24*time.Hour + 4*time.Hour + 12*time.Minute, "up 3 days, 4:12 (since 2024-05-01 10:02)"},
+	}
+	for _, tt := range tests {
+		if got := uptimeDetail(tt.uptime, now); got != tt.want {
+			t.Errorf("uptimeDetail(%v) = %q, want %q", tt.uptime, got, tt.want)
+		}
+	}
+}
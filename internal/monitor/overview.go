@@ -0,0 +1,117 @@
+package monitor
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DiskUsage is the space used on one mounted filesystem.
+type DiskUsage struct {
+	Mount  string
+	SizeKB float64
+	UsedKB float64
+	// Pct is the share of the filesystem in use, as df reports it.
+	Pct float64
+}
+
+// Process is one row of the top-processes list.
+type Process struct {
+	PID int
+	// CPU is the share of one core, Mem the share of memory, in percent.
+	CPU     float64
+	Mem     float64
+	Command string
+}
+
+// OverviewSample is what the overview tab shows beyond the metrics row.
+type OverviewSample struct {
+	Disks []DiskUsage
+	Procs []Process
+	// ProcCount is how many processes ps listed, before the top cut.
+	ProcCount int
+}
+
+// SampleOverview measures each of mounts and lists the top processes by
+// CPU. Mounts df cannot read are left out.
+func SampleOverview(parent context.Context, mounts []string, top int) OverviewSample {
+	if Demo {
+		return demoOverview(mounts, top)
+	}
+	ctx, cancel := context.WithTimeout(parent, sampleBudget)
+	defer cancel()
+
+	var sample OverviewSample
+	if _, err := lookPath("df"); err == nil {
+		for _, mount := range mounts {
+			out, err := runQuickCmd(ctx, []string{"df", "-kP", mount}, 2*time.Second)
+			if err != nil {
+				continue
+			}
+			if disk, ok := parseDfPosix(out); ok {
+				sample.Disks = append(sample.Disks, disk)
+			}
+		}
+	}
+	if _, err := lookPath("ps"); err == nil {
+		// Empty column names drop the header on both procps and BSD ps.
+		if out, err := runQuickCmd(ctx, []string{"ps", "-Ao", "pid=,pcpu=,pmem=,comm="}, 2*time.Second); err == nil {
+			procs := parsePs(out)
+			sample.ProcCount = len(procs)
+			sample.Procs = topProcesses(procs, top)
+		}
+	}
+	return sample
+}
+
+// parseDfPosix reads the filesystem row of `df -kP`. The mount point is
+// the last column and may contain spaces.
+func parseDfPosix(out string) (DiskUsage, bool) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return DiskUsage{}, false
+	}
+	fields := strings.Fields(lines[1])
+	if len(fields) < 6 {
+		return DiskUsage{}, false
+	}
+	size, err1 := parseFloat(fields[1])
+	used, err2 := parseFloat(fields[2])
+	pct, err3 := parseFloat(strings.TrimSuffix(fields[4], "%"))
+	if err1 != nil || err2 != nil || err3 != nil {
+		return DiskUsage{}, false
+	}
+	return DiskUsage{Mount: strings.Join(fields[5:], " "), SizeKB: size, UsedKB: used, Pct: pct}, true
+}
+
+// parsePs reads `ps -Ao pid=,pcpu=,pmem=,comm=` output, skipping rows it
+// cannot parse. The command is the rest of the line.
+func parsePs(out string) []Process {
+	var procs []Process
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[0])
+		cpu, err2 := parseFloat(fields[1])
+		mem, err3 := parseFloat(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		procs = append(procs, Process{PID: pid, CPU: cpu, Mem: mem, Command: strings.Join(fields[3:], " ")})
+	}
+	return procs
+}
+
+// topProcesses keeps the n busiest processes, busiest first; ties keep
+// ps order.
+func topProcesses(procs []Process, n int) []Process {
+	sort.SliceStable(procs, func(i, j int) bool { return procs[i].CPU > procs[j].CPU })
+	if len(procs) > n {
+		procs = procs[:n]
+	}
+	return procs
+}
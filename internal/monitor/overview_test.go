@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDfPosix(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want DiskUsage
+		ok   bool
+	}{
+		{
+			"linux",
+			"Filesystem     1024-blocks     Used Available Capacity Mounted on\n/dev/sda1        102400000 40960000  61440000      40% /\n",
+			DiskUsage{Mount: "/", SizeKB: 102400000, UsedKB: 40960000, Pct: 40},
+			true,
+		},
+		{
+			"mount with spaces",
+			"Filesystem 1024-blocks Used Available Capacity Mounted on\n/dev/disk2s1 2000 500 1500 25% /Volumes/My Disk\n",
+			DiskUsage{Mount: "/Volumes/My Disk", SizeKB: 2000, UsedKB: 500, Pct: 25},
+			true,
+		},
+		{"header only", "Filesystem 1024-blocks Used Available Capacity Mounted on\n", DiskUsage{}, false},
+		{"garbage", "Filesystem\nfoo bar baz qux quux /\n", DiskUsage{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseDfPosix(tt.out)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("%s: parseDfPosix() = %+v, %t, want %+v, %t", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParsePsTop(t *testing.T) {
+	out := `    1   0.0  0.1 systemd
+  812  23.5  4.1 postgres
+ 1290  12.0  2.6 nginx: worker process
+ oops
+ 4411  23.5  8.3 java
+`
+	want := []Process{
+		{PID: 812, CPU: 23.5, Mem: 4.1, Command: "postgres"},
+		{PID: 4411, CPU: 23.5, Mem: 8.3, Command: "java"},
+		{PID: 1290, CPU: 12, Mem: 2.6, Command: "nginx: worker process"},
+	}
+	if got := topProcesses(parsePs(out), 3); !reflect.DeepEqual(got, want) {
+		t.Errorf("topProcesses(parsePs()) = %+v, want %+v", got, want)
+	}
+	if got := topProcesses(parsePs(out), 10); len(got) != 4 {
+		t.Errorf("topProcesses(10) kept %d processes, want all 4", len(got))
+	}
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of the times in /proc/<pid>/stat. It is
+// 100 on every mainstream Linux build.
+const clockTicks = 100
+
+// ProcUsage is the resource use of a single process.
+type ProcUsage struct {
+	PID int
+	// CPU is the user plus system time the process has used.
+	CPU time.Duration
+	// RSSBytes is its resident set size.
+	RSSBytes uint64
+}
+
+// ReadProcUsage reads the CPU time and resident memory of pid from
+// /proc/<pid>/stat. It reports false where procfs is unavailable or the
+// process is gone.
+func ReadProcUsage(pid int) (ProcUsage, bool) {
+	data, err := readFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return ProcUsage{}, false
+	}
+	return parseProcPidStat(data, os.Getpagesize())
+}
+
+// parseProcPidStat extracts the pid, utime + stime and rss from a
+// /proc/<pid>/stat line. The command name is parenthesised and may itself
+// contain spaces or parentheses, so the fields are counted from the last
+// ')'.
+func parseProcPidStat(data []byte, pageSize int) (ProcUsage, bool) {
+	open := bytes.IndexByte(data, '(')
+	end := bytes.LastIndexByte(data, ')')
+	if open < 1 || end < open {
+		return ProcUsage{}, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data[:open])))
+	if err != nil {
+		return ProcUsage{}, false
+	}
+	// fields[0] is field 3 (state), so utime (14), stime (15) and rss (24)
+	// sit at 11, 12 and 21.
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 22 {
+		return ProcUsage{}, false
+	}
+	utime, err1 := strconv.ParseUint(fields[11], 10, 64)
+	stime, err2 := strconv.ParseUint(fields[12], 10, 64)
+	rss, err3 := strconv.ParseInt(fields[21], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil || rss < 0 {
+		return ProcUsage{}, false
+	}
+	return ProcUsage{
+		PID:      pid,
+		CPU:      time.Duration(utime+stime) * time.Second / clockTicks,
+		RSSBytes: uint64(rss) * uint64(pageSize),
+	}, true
+}
@@ -0,0 +1,39 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseProcPidStat(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want ProcUsage
+		ok   bool
+	}{
+		{
+			"top",
+			"4242 (top) S 4100 4242 4100 34816 4242 4194560 1053 0 0 0 12 3 0 0 20 0 1 0 35719318 10895360 900 18446744073709551615 1 1 0 0 0 0 0 0 138 0 0 0 17 3 0 0 0 0 0\n",
+			ProcUsage{PID: 4242, CPU: 150 * time.Millisecond, RSSBytes: 900 * 4096},
+			true,
+		},
+		{
+			"name with spaces and parens",
+			"77 (my (odd) tool) R 1 77 77 0 -1 4194304 10 0 0 0 250 50 0 0 20 0 1 0 100 2000 25 18446744073709551615\n",
+			ProcUsage{PID: 77, CPU: 3 * time.Second, RSSBytes: 25 * 4096},
+			true,
+		},
+		{"truncated", "77 (sh) R 1 77 77 0 -1", ProcUsage{}, false},
+		{"no name", "77 R 1 77", ProcUsage{}, false},
+		{"empty", "", ProcUsage{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseProcPidStat([]byte(tt.data), 4096)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("parseProcPidStat = %+v, %t; want %+v, %t", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
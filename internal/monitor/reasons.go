@@ -0,0 +1,27 @@
+package monitor
+
+// Reasons a metric is unavailable, shown in the metrics detail view so a
+// missing tool can be told apart from output that could not be read.
+const (
+	reasonParse   = "parse error"
+	reasonWarming = "waiting for a second sample"
+	// reasonLoopbackOnly means the only interface is loopback, which is
+	// left out of the rate unless net_loopback_fallback is set.
+	reasonLoopbackOnly = "only loopback"
+)
+
+// notFound explains that none of the tools could be found on PATH.
+func notFound(tools string) string {
+	return tools + " not found"
+}
+
+// sourceFailed explains that reading source failed, e.g. "vmstat: exit
+// status 1".
+func sourceFailed(source string, err error) string {
+	return source + ": " + err.Error()
+}
+
+// parseFailed explains that source produced output that could not be read.
+func parseFailed(source string) string {
+	return source + ": " + reasonParse
+}
@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+)
+
+func TestSampleReasons(t *testing.T) {
+	origLookPath, origExecCmd, origReadFile := lookPath, execCmd, readFile
+	t.Cleanup(func() {
+		lookPath, execCmd, readFile = origLookPath, origExecCmd, origReadFile
+		ResetNetBaseline()
+	})
+	resetIntervalCPU(t)
+	readFile = func(string) ([]byte, error) { return nil, os.ErrNotExist }
+
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+	missing := SampleMetrics(context.Background())
+
+	lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	execCmd = func(ctx context.Context, cmd []string) (string, error) { return "garbage\n", nil }
+	// CPU comes from a background vmstat/mpstat run, ready by the next
+	// sample.
+	SampleMetrics(context.Background())
+	waitIntervalCPU(t)
+	garbled := SampleMetrics(context.Background())
+	waitIntervalCPU(t)
+
+	tests := []struct {
+		metric           string
+		missing, garbled string
+	}{
+		{"load", "uptime not found", "uptime: parse error"},
+		{"cpu", "vmstat and mpstat not found", "mpstat: parse error"},
+		{"mem", "free and vm_stat not found", "vm_stat: parse error"},
+		{"net", "netstat not found", "netstat: parse error"},
+	}
+	reasons := func(s MetricsSample) map[string]string {
+		return map[string]string{"load": s.LoadReason, "cpu": s.CPUReason, "mem": s.MemReason, "net": s.NetReason}
+	}
+	for _, tt := range tests {
+		if got := reasons(missing)[tt.metric]; got != tt.missing {
+			t.Errorf("%s with no tools: reason = %q, want %q", tt.metric, got, tt.missing)
+		}
+		if got := reasons(garbled)[tt.metric]; got != tt.garbled {
+			t.Errorf("%s with bad output: reason = %q, want %q", tt.metric, got, tt.garbled)
+		}
+	}
+	if missing.OkLoad || missing.OkCPU || missing.OkMem || missing.OkNet {
+		t.Errorf("expected every metric unavailable, got %+v", missing)
+	}
+}
+
+func TestLoopbackOnlyNetDev(t *testing.T) {
+	origReadFile, origLookPath := readFile, lookPath
+	t.Cleanup(func() {
+		readFile, lookPath = origReadFile, origLookPath
+		SetLoopbackFallback(false)
+	})
+	data := []byte("Inter-|   Receive                                                |  Transmit\n" +
+		" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+		"    lo:    4096      10    0    0    0     0          0         0     1024      10    0    0    0     0       0          0\n")
+	readFile = func(string) ([]byte, error) { return data, nil }
+	lookPath = func(string) (string, error) { return "", exec.ErrNotFound }
+
+	totals := sumNetBytesLinux(data)
+	if totals.found || !totals.foundLoopback || totals.loopback != 5120 {
+		t.Errorf("sumNetBytesLinux = %+v, want only 5120 loopback bytes", totals)
+	}
+
+	if _, ok, reason := readNetBytes(context.Background()); ok || reason != "/proc/net/dev: only loopback" {
+		t.Errorf("without fallback: ok = %t, reason %q; want unavailable as only loopback", ok, reason)
+	}
+	SetLoopbackFallback(true)
+	if total, ok, _ := readNetBytes(context.Background()); !ok || total != 5120 {
+		t.Errorf("with fallback: total = %d, ok = %t; want 5120 from lo", total, ok)
+	}
+
+	readFile = func(string) ([]byte, error) { return []byte("garbage\n"), nil }
+	if _, _, reason := readNetBytes(context.Background()); reason != "/proc/net/dev: parse error" {
+		t.Errorf("no interfaces: reason = %q, want a parse error", reason)
+	}
+}
+
+func TestPeekNetRateKeepsBaseline(t *testing.T) {
+	origReadFile := readFile
+	t.Cleanup(func() {
+		readFile = origReadFile
+		ResetNetBaseline()
+	})
+	ResetNetBaseline()
+	netDev := func(rx int) []byte {
+		return []byte("Inter-|   Receive                                                |  Transmit\n" +
+			" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+			"  eth0: " + strconv.Itoa(rx) + " 10 0 0 0 0 0 0 0 10 0 0 0 0 0 0\n")
+	}
+	data := netDev(0)
+	readFile = func(string) ([]byte, error) { return data, nil }
+
+	if _, ok := peekNetRateKB(context.Background()); ok {
+		t.Error("peekNetRateKB before any sample should have no rate")
+	}
+	if _, ok, _ := getNetRateKB(context.Background()); ok {
+		t.Fatal("first getNetRateKB should only set the baseline")
+	}
+	data = netDev(1 << 20)
+	for i := 0; i < 2; i++ {
+		if rate, ok := peekNetRateKB(context.Background()); !ok || rate <= 0 {
+			t.Errorf("peekNetRateKB = %v, %t; want a positive rate", rate, ok)
+		}
+	}
+	netMu.Lock()
+	prev := netPrevTotal
+	netMu.Unlock()
+	if prev != 0 {
+		t.Errorf("peekNetRateKB moved the baseline to %d", prev)
+	}
+}
@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultIfaceName in net_interfaces stands for the default-route
+// interface.
+const defaultIfaceName = "default"
+
+// rtfUp is the RTF_UP flag of a /proc/net/route entry.
+const rtfUp = 0x1
+
+// defaultRouteIface returns the interface of the default route, read from
+// /proc/net/route on Linux and `route -n get default` on darwin, or "".
+func defaultRouteIface(ctx context.Context) string {
+	if data, err := readFile("/proc/net/route"); err == nil {
+		return parseProcNetRoute(data)
+	}
+	if _, err := lookPath("route"); err == nil {
+		if out, err := runQuickCmd(ctx, []string{"route", "-n", "get", "default"}, 2*time.Second); err == nil {
+			return parseRouteGetDefault(out)
+		}
+	}
+	return ""
+}
+
+// parseProcNetRoute picks the interface of the up route with destination
+// and mask 00000000, preferring the lowest metric when there are several.
+func parseProcNetRoute(data []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 2 {
+		return ""
+	}
+	header := strings.Fields(lines[0])
+	ifIdx := indexOf(header, "Iface")
+	dstIdx := indexOf(header, "Destination")
+	flagsIdx := indexOf(header, "Flags")
+	metricIdx := indexOf(header, "Metric")
+	maskIdx := indexOf(header, "Mask")
+	if ifIdx == -1 || dstIdx == -1 || flagsIdx == -1 || metricIdx == -1 || maskIdx == -1 {
+		return ""
+	}
+	best, bestMetric := "", -1
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) < len(header) {
+			continue
+		}
+		if fields[dstIdx] != "00000000" || fields[maskIdx] != "00000000" {
+			continue
+		}
+		flags, err := strconv.ParseUint(fields[flagsIdx], 16, 32)
+		if err != nil || flags&rtfUp == 0 {
+			continue
+		}
+		metric, err := strconv.Atoi(fields[metricIdx])
+		if err != nil {
+			continue
+		}
+		if bestMetric == -1 || metric < bestMetric {
+			best, bestMetric = fields[ifIdx], metric
+		}
+	}
+	return best
+}
+
+// parseRouteGetDefault reads the "interface:" line of darwin's
+// `route -n get default`.
+func parseRouteGetDefault(out string) string {
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if ok && key == "interface" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// resolveIfaces replaces "default" in names with the default-route
+// interface, leaving it as is when there is no default route.
+func resolveIfaces(ctx context.Context, names []string) []string {
+	out := make([]string, len(names))
+	for i, name := range names {
+		out[i] = name
+		if name == defaultIfaceName {
+			if iface := defaultRouteIface(ctx); iface != "" {
+				out[i] = iface
+			}
+		}
+	}
+	return out
+}
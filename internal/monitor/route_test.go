@@ -0,0 +1,44 @@
+package monitor
+
+import "testing"
+
+func TestParseProcNetRoute(t *testing.T) {
+	const header = "Iface\tDestination\tGateway \tFlags\tRefCnt\tUse\tMetric\tMask\t\tMTU\tWindow\tIRTT\n"
+	tests := []struct {
+		name, data, want string
+	}{
+		{
+			"default after a local route",
+			header +
+				"docker0\t000011AC\t00000000\t0001\t0\t0\t0\t0000FFFF\t0\t0\t0\n" +
+				"eth1\t00000000\t0101A8C0\t0003\t0\t0\t0\t00000000\t0\t0\t0\n",
+			"eth1",
+		},
+		{
+			"lowest metric wins",
+			header +
+				"wlan0\t00000000\t0101A8C0\t0003\t0\t0\t600\t00000000\t0\t0\t0\n" +
+				"eth0\t00000000\t0100000A\t0003\t0\t0\t100\t00000000\t0\t0\t0\n",
+			"eth0",
+		},
+		{
+			"down route ignored",
+			header + "eth0\t00000000\t0100000A\t0002\t0\t0\t0\t00000000\t0\t0\t0\n",
+			"",
+		},
+		{"no default", header + "eth0\t0000A8C0\t00000000\t0001\t0\t0\t0\t00FFFFFF\t0\t0\t0\n", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		if got := parseProcNetRoute([]byte(tt.data)); got != tt.want {
+			t.Errorf("%s: parseProcNetRoute = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseRouteGetDefault(t *testing.T) {
+	out := "   route to: default\ndestination: default\n       mask: default\n    gateway: 192.168.1.1\n  interface: en0\n      flags: <UP,GATEWAY,DONE,STATIC,PRCLONING>\n"
+	if got := parseRouteGetDefault(out); got != "en0" {
+		t.Errorf("parseRouteGetDefault = %q, want en0", got)
+	}
+}
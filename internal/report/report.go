@@ -0,0 +1,100 @@
+// Package report renders a one-shot markdown snapshot of every tab for
+// incident notes.
+package report
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+// Runner executes a tab command and returns stdout and stderr separately,
+// matching the TUI's runner so the same implementation serves both.
+type Runner func(ctx context.Context, cmd []string) (stdout, stderr string, err error)
+
+const cmdTimeout = 4 * time.Second
+
+// Hooks, swapped out in tests.
+var (
+	sampleMetrics = monitor.SampleMetrics
+	systemSummary = monitor.SystemSummaryText
+	now           = time.Now
+)
+
+// BuildReport runs every enabled tab once and renders a markdown document:
+// a metrics summary table followed by one section per tab with its output
+// in a fenced code block.
+func BuildReport(tabs []config.Tab, run Runner) string {
+	var b strings.Builder
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "unknown"
+	}
+	fmt.Fprintf(&b, "# perfdeck report\n\nGenerated %s on %s.\n\n", now().Format(time.RFC3339), host)
+
+	writeMetrics(&b, sampleMetrics(context.Background()))
+
+	for _, t := range tabs {
+		if t.Disabled || t.Builtin == config.BuiltinOverview {
+			// The overview only restates the metrics table.
+			continue
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", t.Title)
+		output, err := runOnce(t, run)
+		if len(t.Cmd) > 0 {
+			fmt.Fprintf(&b, "`%s`\n\n", strings.Join(t.Cmd, " "))
+		}
+		if err != nil {
+			fmt.Fprintf(&b, "> error: %v\n\n", err)
+		}
+		output = strings.TrimRight(output, "\n")
+		if output == "" {
+			output = "(no output)"
+		}
+		fmt.Fprintf(&b, "```\n%s\n```\n", output)
+	}
+	return b.String()
+}
+
+func writeMetrics(b *strings.Builder, s monitor.MetricsSample) {
+	value := func(ok bool, format string, args ...any) string {
+		if !ok {
+			return "n/a"
+		}
+		return fmt.Sprintf(format, args...)
+	}
+	mem := value(s.OkMem, "%0.0f%%", s.Mem)
+	if s.OkMem && s.MemTotalMB > 0 {
+		mem += fmt.Sprintf(" (%s/%sG)", monitor.FormatGB(s.MemUsedMB), monitor.FormatGB(s.MemTotalMB))
+	}
+	rows := [][2]string{
+		{"CPU", value(s.OkCPU, "%0.0f%%", s.CPU)},
+		{"MEM", mem},
+		{"LOAD", value(s.OkLoad, "%0.2f %0.2f %0.2f", s.Load, s.Load5, s.Load15)},
+		{"NET", value(s.OkNet, "%s", monitor.FormatRate(s.NetKB))},
+	}
+	b.WriteString("## Metrics\n\n| Metric | Value |\n|:---|:---|\n")
+	for _, row := range rows {
+		fmt.Fprintf(b, "| %s | %s |\n", row[0], row[1])
+	}
+}
+
+// runOnce runs a tab with the same per-command timeout as the TUI. Output
+// falls back to stderr when the command failed without writing stdout.
+func runOnce(t config.Tab, run Runner) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cmdTimeout)
+	defer cancel()
+	if t.Builtin == config.BuiltinAbout {
+		return systemSummary(ctx), nil
+	}
+	stdout, stderr, err := run(ctx, t.Cmd)
+	if strings.TrimSpace(stdout) == "" && err != nil {
+		return stderr, err
+	}
+	return stdout, err
+}
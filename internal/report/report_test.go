@@ -0,0 +1,64 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+func TestBuildReport(t *testing.T) {
+	sampleMetrics = func(context.Context) monitor.MetricsSample {
+		return monitor.MetricsSample{CPU: 42, OkCPU: true, Load: 1, Load5: 2, Load15: 3, OkLoad: true}
+	}
+	systemSummary = func(context.Context) string { return "Host: test\n" }
+	now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+	t.Cleanup(func() {
+		sampleMetrics = monitor.SampleMetrics
+		systemSummary = monitor.SystemSummaryText
+		now = time.Now
+	})
+
+	tabs := []config.Tab{
+		{Title: "uptime", Cmd: []string{"uptime"}},
+		{Title: "broken", Cmd: []string{"broken", "-x"}},
+		{Title: "off", Cmd: []string{"off"}, Disabled: true},
+		{Title: "overview", Builtin: config.BuiltinOverview},
+		{Title: "about", Builtin: config.BuiltinAbout},
+	}
+	var ran []string
+	run := func(ctx context.Context, cmd []string) (string, string, error) {
+		ran = append(ran, cmd[0])
+		if cmd[0] == "broken" {
+			return "", "no such flag", errors.New("exit status 2")
+		}
+		return "up 3 days\n", "", nil
+	}
+
+	got := BuildReport(tabs, run)
+
+	if strings.Join(ran, ",") != "uptime,broken" {
+		t.Errorf("ran %v, want only the enabled command tabs", ran)
+	}
+	for _, want := range []string{
+		"# perfdeck report\n",
+		"Generated 2024-05-01T12:00:00Z",
+		"| Metric | Value |\n|:---|:---|\n| CPU | 42% |\n| MEM | n/a |\n| LOAD | 1.00 2.00 3.00 |\n| NET | n/a |\n",
+		"\n## uptime\n\n`uptime`\n\n```\nup 3 days\n```\n",
+		"\n## broken\n\n`broken -x`\n\n> error: exit status 2\n\n```\nno such flag\n```\n",
+		"\n## about\n\n```\nHost: test\n```\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report missing %q:\n%s", want, got)
+		}
+	}
+	for _, unwanted := range []string{"## off", "## overview"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("report should not contain %q", unwanted)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package spark renders numeric series as one-line sparklines.
+package spark
+
+import (
+	"math"
+	"strings"
+)
+
+// ASCII is the default ramp, from lowest to highest level.
+var ASCII = []rune(" .:-=+*#%@")
+
+// Gap is drawn for NaN values, which mark missing samples.
+const Gap = '·'
+
+// Render maps each value onto ramp, scaled between min and max. Values
+// outside the range are clamped and NaN values render as Gap; an empty
+// series or ramp renders nothing.
+func Render(values []float64, min, max float64, ramp []rune) string {
+	if len(values) == 0 || len(ramp) == 0 {
+		return ""
+	}
+	if max <= min {
+		max = min + 1
+	}
+	var b strings.Builder
+	for _, v := range values {
+		if math.IsNaN(v) {
+			b.WriteRune(Gap)
+			continue
+		}
+		b.WriteRune(ramp[level(v, min, max, len(ramp))])
+	}
+	return b.String()
+}
+
+// level returns the ramp index, in [0, levels), for v scaled between min
+// and max.
+func level(v, min, max float64, levels int) int {
+	if max <= min {
+		max = min + 1
+	}
+	if v < min {
+		v = min
+	}
+	if v > max {
+		v = max
+	}
+	n := int(((v - min) / (max - min)) * float64(levels-1))
+	if n < 0 {
+		n = 0
+	}
+	if n >= levels {
+		n = levels - 1
+	}
+	return n
+}
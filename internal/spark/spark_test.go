@@ -0,0 +1,31 @@
+package spark
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		min, max float64
+		ramp     []rune
+		want     string
+	}{
+		{"empty", nil, 0, 100, ASCII, ""},
+		{"empty ramp", []float64{1}, 0, 100, nil, ""},
+		{"scaled", []float64{0, 50, 100}, 0, 100, ASCII, " =@"},
+		{"clamped", []float64{-10, 200}, 0, 100, ASCII, " @"},
+		{"flat range", []float64{5, 5}, 5, 5, ASCII, "  "},
+		{"custom ramp", []float64{0, 1}, 0, 1, []rune("ab"), "ab"},
+		{"gap", []float64{0, math.NaN(), 100}, 0, 100, ASCII, " ·@"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.values, tt.min, tt.max, tt.ramp); got != tt.want {
+				t.Errorf("Render(%v) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,14 @@
 package theme
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/sumant1122/perfdeck/internal/spark"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// blockRamp is a heavier sparkline ramp for light backgrounds, where the
+// faint ASCII levels wash out.
+var blockRamp = []rune(" ▁▂▃▄▅▆▇█")
 
 type Theme struct {
 	Name       string
@@ -9,6 +17,12 @@ type Theme struct {
 	Ink        string
 	Muted      string
 	Background string
+	// Mono themes draw every severity in the same high-contrast colors, so
+	// the UI marks thresholds with glyphs instead.
+	Mono bool
+	// Ramp overrides the sparkline levels, lowest first; nil means
+	// spark.ASCII.
+	Ramp []rune
 }
 
 var Themes = []Theme{
@@ -35,6 +49,16 @@ var Themes = []Theme{
 		Ink:        "#0B1220",
 		Muted:      "#506072",
 		Background: "#F7FAFF",
+		Ramp:       blockRamp,
+	},
+	{
+		Name:       "Mono",
+		Accent:     "#FFFFFF",
+		AccentDark: "#000000",
+		Ink:        "#FFFFFF",
+		Muted:      "#FFFFFF",
+		Background: "#000000",
+		Mono:       true,
 	},
 }
 
@@ -59,13 +83,30 @@ type Styles struct {
 	Ink        lipgloss.Color
 	Muted      lipgloss.Color
 	Background lipgloss.Color
+	// Mono is set for high-contrast themes that cannot tell severities
+	// apart by color.
+	Mono bool
+	// Ramp is the sparkline levels for this theme, lowest first.
+	Ramp []rune
 }
 
-func BuildStyles(index int) Styles {
-	if index < 0 || index >= len(Themes) {
-		index = 0
+// Count is the number of built-in themes.
+func Count() int {
+	return len(Themes)
+}
+
+// Normalize maps any index onto a valid theme, wrapping around in both
+// directions so cycling past the last theme returns to the first.
+func Normalize(i int) int {
+	n := Count()
+	if n == 0 {
+		return 0
 	}
-	t := Themes[index]
+	return (i%n + n) % n
+}
+
+func BuildStyles(index int) Styles {
+	t := Themes[Normalize(index)]
 
 	s := Styles{}
 	s.Accent = lipgloss.Color(t.Accent)
@@ -73,6 +114,10 @@ func BuildStyles(index int) Styles {
 	s.Ink = lipgloss.Color(t.Ink)
 	s.Muted = lipgloss.Color(t.Muted)
 	s.Background = lipgloss.Color(t.Background)
+	s.Ramp = t.Ramp
+	if len(s.Ramp) == 0 {
+		s.Ramp = spark.ASCII
+	}
 
 	s.Header = lipgloss.NewStyle().Foreground(s.Ink).Background(s.Background).Padding(0, 1)
 	s.ActiveTab = lipgloss.NewStyle().Foreground(s.Background).Background(s.Accent).Bold(true).Padding(0, 1)
@@ -92,6 +137,12 @@ func BuildStyles(index int) Styles {
 	s.Yellow = lipgloss.NewStyle().Foreground(lipgloss.Color("#facc15")).Background(s.AccentDark)
 	s.Red = lipgloss.NewStyle().Foreground(lipgloss.Color("#f87171")).Background(s.AccentDark)
 	s.Processing = lipgloss.NewStyle().Foreground(s.Muted).Background(s.AccentDark)
+	if t.Mono {
+		s.Mono = true
+		s.Green = lipgloss.NewStyle().Foreground(s.Ink).Background(s.AccentDark)
+		s.Yellow = s.Green.Bold(true)
+		s.Red = s.Green.Bold(true).Reverse(true)
+	}
 
 	return s
 }
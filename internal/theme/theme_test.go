@@ -0,0 +1,50 @@
+package theme
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/spark"
+)
+
+func TestNormalize(t *testing.T) {
+	n := Count()
+	tests := []struct {
+		in, want int
+	}{
+		{0, 0},
+		{n - 1, n - 1},
+		{n, 0},
+		{n + 1, 1},
+		{-1, n - 1},
+		{-n, 0},
+		{-n - 1, n - 1},
+		{10*n + 2, 2},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%d) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuildStylesRamp(t *testing.T) {
+	for i, th := range Themes {
+		want := spark.ASCII
+		if th.Name == "Day" {
+			want = blockRamp
+		}
+		if got := BuildStyles(i).Ramp; !slices.Equal(got, want) {
+			t.Errorf("%s ramp = %q, want %q", th.Name, string(got), string(want))
+		}
+	}
+}
+
+func TestBuildStylesOutOfRange(t *testing.T) {
+	for _, i := range []int{-1, Count(), 1 << 20} {
+		got := BuildStyles(i)
+		if want := BuildStyles(Normalize(i)); got.Accent != want.Accent {
+			t.Errorf("BuildStyles(%d) accent = %v, want %v", i, got.Accent, want.Accent)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// alertReasons lists the thresholds s crosses, e.g. "CPU 95% >= 90%".
+func alertReasons(cfg config.Config, s monitor.MetricsSample) []string {
+	var reasons []string
+	if cfg.AlertCPU > 0 && s.OkCPU && s.CPU >= cfg.AlertCPU {
+		reasons = append(reasons, fmt.Sprintf("CPU %0.0f%% >= %0.0f%%", s.CPU, cfg.AlertCPU))
+	}
+	if cfg.AlertMem > 0 && s.OkMem && s.Mem >= cfg.AlertMem {
+		reasons = append(reasons, fmt.Sprintf("MEM %0.0f%% >= %0.0f%%", s.Mem, cfg.AlertMem))
+	}
+	return reasons
+}
+
+// risingEdge reports whether an alert has just started. Alerts only act
+// on the rising edge, so a metric hovering at the threshold does not keep
+// announcing itself or pulling the user back.
+func risingEdge(wasAlerting, alerting bool) bool {
+	return alerting && !wasAlerting
+}
+
+// alertFocus decides which tab to switch to for a new alert, reporting
+// false when the focus tab is missing, disabled or already active.
+func alertFocus(tabs []config.Tab, active int, title string) (int, bool) {
+	if title == "" {
+		return 0, false
+	}
+	idx := tabIndexByTitle(tabs, title)
+	if idx < 0 || idx == active || tabs[idx].Disabled {
+		return 0, false
+	}
+	return idx, true
+}
+
+// checkAlert updates the alert state from the latest sample, announcing a
+// new alert and switching to the on_alert_focus tab.
+func (m *Model) checkAlert() tea.Cmd {
+	reasons := alertReasons(m.cfg, m.sample)
+	alerting := len(reasons) > 0
+	wasAlerting := m.alerting
+	m.alerting = alerting
+	if !risingEdge(wasAlerting, alerting) {
+		return nil
+	}
+	m.setNotice("alert: " + strings.Join(reasons, ", "))
+	idx, ok := alertFocus(m.tabs, m.active, m.cfg.OnAlertFocus)
+	if !ok {
+		return nil
+	}
+	m.active = idx
+	return m.onTabSelected()
+}
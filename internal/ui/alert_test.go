@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+func TestRisingEdge(t *testing.T) {
+	tests := []struct {
+		name                  string
+		wasAlerting, alerting bool
+		want                  bool
+	}{
+		{"rising edge", false, true, true},
+		{"still alerting", true, true, false},
+		{"cleared", true, false, false},
+		{"quiet", false, false, false},
+	}
+	for _, tt := range tests {
+		if got := risingEdge(tt.wasAlerting, tt.alerting); got != tt.want {
+			t.Errorf("%s: risingEdge = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAlertFocus(t *testing.T) {
+	tabs := []config.Tab{
+		{Title: "overview"},
+		{Title: "top"},
+		{Title: "off", Disabled: true},
+	}
+	tests := []struct {
+		name    string
+		active  int
+		title   string
+		wantIdx int
+		wantOk  bool
+	}{
+		{"focus tab", 0, "top", 1, true},
+		{"no focus tab", 0, "", 0, false},
+		{"unknown tab", 0, "missing", 0, false},
+		{"already active", 1, "top", 0, false},
+		{"disabled tab", 0, "off", 0, false},
+	}
+	for _, tt := range tests {
+		idx, ok := alertFocus(tabs, tt.active, tt.title)
+		if idx != tt.wantIdx || ok != tt.wantOk {
+			t.Errorf("%s: alertFocus = %d, %t; want %d, %t", tt.name, idx, ok, tt.wantIdx, tt.wantOk)
+		}
+	}
+}
+
+func TestAlertSwitchesOnRisingEdgeOnly(t *testing.T) {
+	m := NewModel()
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		return "out", "", nil
+	}
+	m.tabs = []config.Tab{{Title: "overview", Cmd: []string{"a"}}, {Title: "top", Cmd: []string{"b"}}}
+	m.cfg.AlertCPU = 90
+	m.cfg.OnAlertFocus = "top"
+
+	send := func(cpu float64) {
+		updated, _ := m.Update(metricsMsg{metrics: monitor.MetricsSample{CPU: cpu, OkCPU: true}})
+		next, ok := updated.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+	}
+
+	send(50)
+	if m.active != 0 {
+		t.Fatalf("switched tabs below the threshold")
+	}
+	send(95)
+	if m.active != 1 {
+		t.Fatalf("alert should focus the top tab, active = %d", m.active)
+	}
+	if m.notice == "" {
+		t.Error("alert should show a notice")
+	}
+
+	// The user moves away while the alert persists; it must not flap back.
+	m.active = 0
+	send(96)
+	if m.active != 0 {
+		t.Error("a continuing alert switched tabs again")
+	}
+	send(40)
+	send(97)
+	if m.active != 1 {
+		t.Error("a new alert should switch tabs again")
+	}
+}
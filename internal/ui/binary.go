@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// imageHiddenMsg replaces output that looksBinary flags.
+const imageHiddenMsg = "(image output hidden)"
+
+// binaryThreshold is the share of non-printable runes above which output
+// is treated as binary.
+const binaryThreshold = 0.1
+
+// imagePrefixes start terminal graphics protocols: DCS (sixel), the kitty
+// graphics APC and iTerm2 inline images.
+var imagePrefixes = []string{"\x1bP", "\x1b_G", "\x1b]1337;File="}
+
+// looksBinary reports whether s holds terminal image data or is mostly
+// non-printable bytes, either of which would corrupt the viewport.
+func looksBinary(s string) bool {
+	for _, prefix := range imagePrefixes {
+		if strings.Contains(s, prefix) {
+			return true
+		}
+	}
+	text := ansi.Strip(s)
+	var total, bad int
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+		total++
+		switch {
+		case r == utf8.RuneError && size == 1:
+			bad++
+		case r == '\n' || r == '\t' || r == '\r':
+		case !unicode.IsPrint(r) && !unicode.IsSpace(r):
+			bad++
+		}
+	}
+	return total > 0 && float64(bad)/float64(total) > binaryThreshold
+}
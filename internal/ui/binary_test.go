@@ -0,0 +1,27 @@
+package ui
+
+import "testing"
+
+func TestLooksBinary(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{"empty", "", false},
+		{"plain text", "load average: 0.52, 0.58\nup 3 days", false},
+		{"colored text", "\x1b[1;32mOS\x1b[0m: Ubuntu 22.04 ✓", false},
+		{"sixel", "\x1bPq#0;2;0;0;0#1;2;100;100;0#1~~@@vv@@~~@@~~$\x1b\\\nOS: Linux", true},
+		{"kitty graphics", "\x1b_Gf=100,a=T;iVBORw0KGgo=\x1b\\", true},
+		{"iterm image", "\x1b]1337;File=inline=1:AAAA\a", true},
+		{"raw bytes", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x01\x00", true},
+		{"stray control char", "mostly fine text here\x07 with a bell", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := looksBinary(tt.input); got != tt.expected {
+				t.Errorf("looksBinary(%q) = %t, want %t", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// cacheFresh reports whether res, the cached result for t, is still within
+// the tab's cache_ttl at now and can be shown instead of running t again.
+func cacheFresh(t config.Tab, res cmdResultMsg, now time.Time) bool {
+	return t.CacheTTL.Duration > 0 && res.err == nil && now.Sub(res.at) < t.CacheTTL.Duration
+}
+
+// cachedFresh is cacheFresh for tab i of the model.
+func (m Model) cachedFresh(i int, now time.Time) bool {
+	res, ok := m.cache[i]
+	return ok && cacheFresh(m.tabs[i], res, now)
+}
+
+// forceRefresh drops the active tab's cached output, including a static
+// tab's or one still within its cache_ttl, and runs it again.
+func (m *Model) forceRefresh() tea.Cmd {
+	if m.tabs[m.active].Disabled {
+		return nil
+	}
+	delete(m.cache, m.active)
+	m.statusLine = "refreshing..."
+	return m.startRefresh()
+}
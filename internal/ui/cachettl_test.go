@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+)
+
+func TestCacheTTLSkipsTick(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "slow", Cmd: []string{"fake"}}}
+	m.tabs[0].CacheTTL.Duration = time.Minute
+	m.active = 0
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		return "expensive", "", nil
+	}
+
+	newM, _ := m.Update(runCommandCmd(context.Background(), 0, 0, m.tabs[0], m.run)())
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if _, ok := m.cache[0]; !ok {
+		t.Fatal("result should be cached for a tab with cache_ttl")
+	}
+
+	newM, _ = m.Update(tickMsg(time.Now()))
+	m, ok = newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if m.inFlight[0] {
+		t.Error("tick within the TTL should not run the command")
+	}
+	if m.content != "expensive" {
+		t.Errorf("content = %q, want the cached output", m.content)
+	}
+
+	// Once the TTL has passed the next tick runs it again.
+	res := m.cache[0]
+	res.at = time.Now().Add(-2 * time.Minute)
+	m.cache[0] = res
+	if cmd := m.startRefresh(); cmd == nil {
+		t.Error("expired cache should run the command")
+	}
+}
+
+func TestForceRefreshIgnoresTTL(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "slow", Cmd: []string{"fake"}}}
+	m.tabs[0].CacheTTL.Duration = time.Minute
+	m.active = 0
+	m.cache[0] = cmdResultMsg{tab: 0, output: "old", at: time.Now()}
+
+	if cmd := m.startRefresh(); cmd != nil {
+		t.Fatal("fresh cache should not run the command")
+	}
+	if cmd := m.forceRefresh(); cmd == nil {
+		t.Error("forceRefresh should run the command within the TTL")
+	}
+	if _, ok := m.cache[0]; ok {
+		t.Error("forceRefresh should drop the cached result")
+	}
+}
+
+func TestCacheFresh(t *testing.T) {
+	now := time.Now()
+	tab := config.Tab{}
+	tab.CacheTTL.Duration = 30 * time.Second
+	tests := []struct {
+		name string
+		tab  config.Tab
+		res  cmdResultMsg
+		want bool
+	}{
+		{"within ttl", tab, cmdResultMsg{at: now.Add(-10 * time.Second)}, true},
+		{"expired", tab, cmdResultMsg{at: now.Add(-time.Minute)}, false},
+		{"no ttl", config.Tab{}, cmdResultMsg{at: now}, false},
+		{"error", tab, cmdResultMsg{at: now, err: context.Canceled}, false},
+	}
+	for _, tt := range tests {
+		if got := cacheFresh(tt.tab, tt.res, now); got != tt.want {
+			t.Errorf("%s: cacheFresh = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// centerBody places body in the middle of a width x height area when it is
+// shorter than height, for center_content. Output that fills the viewport
+// is left alone so it scrolls as usual.
+func centerBody(body string, width, height int) string {
+	if width <= 0 || lineCount(body) >= height {
+		return body
+	}
+	// Pad lines to a common width first: Place centers each line on its
+	// own, which would break up table columns.
+	lines := strings.Split(body, "\n")
+	block := lipgloss.Width(body)
+	for i, l := range lines {
+		lines[i] = l + strings.Repeat(" ", block-ansi.StringWidth(l))
+	}
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, strings.Join(lines, "\n"))
+}
@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCenterBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		width, height int
+		want          string
+	}{
+		{"centered", "ab", 6, 3, "      \n  ab  \n      "},
+		{"block keeps its alignment", "a\nbbb", 5, 4, "     \n a   \n bbb \n     "},
+		{"fills the height", "a\nb", 5, 2, "a\nb"},
+		{"no width", "a", 0, 3, "a"},
+	}
+	for _, tt := range tests {
+		if got := centerBody(tt.body, tt.width, tt.height); got != tt.want {
+			t.Errorf("%s: centerBody(%q, %d, %d) = %q, want %q", tt.name, tt.body, tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestCenterContent(t *testing.T) {
+	const output = "up 3 days"
+	// position finds the row and column of output within the rendered view.
+	position := func(center bool) (row, col int) {
+		m := NewModel()
+		m.width, m.height = 80, 30
+		m.cfg.CenterContent = center
+		m.setContent(output, 0)
+		for i, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+			if col := strings.Index(line, output); col != -1 {
+				return i, col
+			}
+		}
+		t.Fatalf("center=%v: %q not rendered", center, output)
+		return 0, 0
+	}
+
+	topRow, leftCol := position(false)
+	row, col := position(true)
+	if row <= topRow+5 || row >= 25 {
+		t.Errorf("centered row = %d, want well below the top row %d", row, topRow)
+	}
+	if col < 30 || col > 45 {
+		t.Errorf("centered column = %d, want near the middle (left-aligned at %d)", col, leftCol)
+	}
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+)
+
+// refreshRemaining is the time left until the next tick, given the tick
+// interval and when the last tick fired. It never goes negative.
+func refreshRemaining(interval time.Duration, lastTick, now time.Time) time.Duration {
+	if rem := interval - now.Sub(lastTick); rem > 0 {
+		return rem
+	}
+	return 0
+}
+
+// countdownText renders the time to the next refresh for the footer, e.g.
+// "next refresh in 3s", rounding up so it only reads 0s once it is due.
+// Tabs that never refresh get no countdown, and neither does low-bandwidth
+// mode, where the spinner ticks that would redraw it are off and it would
+// sit frozen.
+func (m Model) countdownText(now time.Time) string {
+	t := m.tabs[m.active]
+	if m.lowBandwidth || t.Disabled || (t.Static && !m.cfg.Prefetch) {
+		return ""
+	}
+	rem := refreshRemaining(m.refreshInterval(), m.lastTick, now)
+	return fmt.Sprintf("next refresh in %ds", int((rem+time.Second-1)/time.Second))
+}
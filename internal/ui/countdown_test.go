@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+)
+
+func TestRefreshRemaining(t *testing.T) {
+	last := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		want    time.Duration
+	}{
+		{"just ticked", 0, 5 * time.Second},
+		{"midway", 2 * time.Second, 3 * time.Second},
+		{"due", 5 * time.Second, 0},
+		{"overdue", 7 * time.Second, 0},
+	}
+	for _, tt := range tests {
+		if got := refreshRemaining(5*time.Second, last, last.Add(tt.elapsed)); got != tt.want {
+			t.Errorf("%s: refreshRemaining = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCountdownText(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "a", Cmd: []string{"echo"}}, {Title: "b", Cmd: []string{"echo"}, Static: true}}
+	m.tabs[0].RefreshInterval.Duration = 5 * time.Second
+	now := time.Now()
+	m.lastTick = now.Add(-1500 * time.Millisecond)
+
+	if got := m.countdownText(now); got != "next refresh in 4s" {
+		t.Errorf("countdownText = %q, want rounded-up seconds", got)
+	}
+	m.active = 1
+	if got := m.countdownText(now); got != "" {
+		t.Errorf("static tab countdown = %q, want none", got)
+	}
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"encoding/csv"
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// toCSV turns whitespace-aligned tabular output into CSV, one row per
+// non-blank line split on runs of whitespace. Fields with commas or quotes
+// are quoted. It is a heuristic: a header with one field fewer than the
+// first row (free's blank corner) gets an empty first column, and one with
+// an extra field (df's "Mounted on") has its last two fields joined. If
+// the rows cannot be written, content is returned as is.
+func toCSV(content string) string {
+	var rows [][]string
+	for _, line := range strings.Split(ansi.Strip(content), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			rows = append(rows, fields)
+		}
+	}
+	if len(rows) > 1 {
+		rows[0] = alignHeader(rows[0], len(rows[1]))
+	}
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.WriteAll(rows); err != nil {
+		log.Printf("csv: %v", err)
+		return content
+	}
+	return b.String()
+}
+
+// alignHeader fixes a header that is off by one field from width, the
+// common cases being an unlabelled first column or a label with a space.
+func alignHeader(header []string, width int) []string {
+	switch len(header) - width {
+	case -1:
+		return append([]string{""}, header...)
+	case 1:
+		n := len(header)
+		return append(header[:n-2:n-2], header[n-2]+" "+header[n-1])
+	}
+	return header
+}
@@ -0,0 +1,37 @@
+package ui
+
+import "testing"
+
+func TestToCSV(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			"free -m",
+			"               total        used        free      shared  buff/cache   available\n" +
+				"Mem:           15890        4120        8011         512        3758       10921\n" +
+				"Swap:           2047           0        2047\n",
+			",total,used,free,shared,buff/cache,available\n" +
+				"Mem:,15890,4120,8011,512,3758,10921\n" +
+				"Swap:,2047,0,2047\n",
+		},
+		{
+			"df -h",
+			"Filesystem      Size  Used Avail Use% Mounted on\n" +
+				"/dev/sda1        50G   20G   28G  42% /\n" +
+				"\n" +
+				"tmpfs           7.8G     0  7.8G   0% /mnt/a,b\n",
+			"Filesystem,Size,Used,Avail,Use%,Mounted on\n" +
+				"/dev/sda1,50G,20G,28G,42%,/\n" +
+				"tmpfs,7.8G,0,7.8G,0%,\"/mnt/a,b\"\n",
+		},
+		{"quotes", `say "hi"`, "say,\"\"\"hi\"\"\"\n"},
+		{"ansi", "\x1b[31mred\x1b[0m  1", "red,1\n"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		if got := toCSV(tt.in); got != tt.want {
+			t.Errorf("%s: toCSV =\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+	}
+}
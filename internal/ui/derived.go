@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"log"
+	"strings"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+// derivedMetric is a [[derived]] config entry with its parsed expression.
+type derivedMetric struct {
+	name string
+	expr *monitor.Expr
+}
+
+// compileDerived parses the derived metric definitions, logging and
+// skipping any that are unnamed or do not parse.
+func compileDerived(defs []config.Derived) []derivedMetric {
+	var out []derivedMetric
+	for _, d := range defs {
+		name := strings.TrimSpace(d.Name)
+		if name == "" {
+			log.Printf("config: derived metric %q has no name, skipped", d.Expr)
+			continue
+		}
+		expr, err := monitor.ParseExpr(d.Expr)
+		if err != nil {
+			log.Printf("config: derived metric %q: %v", name, err)
+			continue
+		}
+		out = append(out, derivedMetric{name: name, expr: expr})
+	}
+	return out
+}
+
+// updateDerived evaluates every derived metric against sample. A metric
+// whose inputs are missing or that divides by zero skips this sample.
+func (m *Model) updateDerived(sample monitor.MetricsSample) {
+	if len(m.derivedHistory) != len(m.derived) {
+		m.derivedHistory = make([][]float64, len(m.derived))
+	}
+	vars := monitor.ExprValues(sample)
+	for i, d := range m.derived {
+		v, err := d.expr.Eval(vars)
+		if err != nil {
+			continue
+		}
+		m.derivedHistory[i] = monitor.AppendHistory(m.derivedHistory[i], v)
+	}
+}
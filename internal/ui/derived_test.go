@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+func TestCompileDerived(t *testing.T) {
+	got := compileDerived([]config.Derived{
+		{Name: "avg", Expr: "(cpu + mem) / 2"},
+		{Name: "", Expr: "cpu"},
+		{Name: "bad", Expr: "disk * 2"},
+		{Name: "ratio", Expr: "cpu / mem"},
+	})
+	if len(got) != 2 || got[0].name != "avg" || got[1].name != "ratio" {
+		t.Errorf("compileDerived kept %+v, want avg and ratio", got)
+	}
+}
+
+func TestDerivedMetricBlock(t *testing.T) {
+	m := NewModel()
+	m.derived = compileDerived([]config.Derived{
+		{Name: "avg", Expr: "(cpu + mem) / 2"},
+		{Name: "ratio", Expr: "cpu / mem"},
+	})
+
+	newM, _ := m.Update(metricsMsg{metrics: monitor.MetricsSample{CPU: 40, OkCPU: true, Mem: 60, OkMem: true}})
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	// mem is 0 here, so ratio divides by zero and skips the sample.
+	newM, _ = m.Update(metricsMsg{metrics: monitor.MetricsSample{CPU: 20, OkCPU: true, Mem: 0, OkMem: true}})
+	m, ok = newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+
+	if got := m.derivedHistory[0]; len(got) != 2 || got[0] != 50 || got[1] != 10 {
+		t.Errorf("avg history = %v, want [50 10]", got)
+	}
+	if got := m.derivedHistory[1]; len(got) != 1 {
+		t.Errorf("ratio history = %v, want one sample", got)
+	}
+
+	row := ansi.Strip(m.renderMetricsRow(m.metrics, 200))
+	for _, want := range []string{"AVG 10.0", "RATIO 0.7"} {
+		if !strings.Contains(row, want) {
+			t.Errorf("metrics row missing %q: %q", want, row)
+		}
+	}
+}
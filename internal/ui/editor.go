@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorClosedMsg reports that the config editor exited.
+type editorClosedMsg struct {
+	err error
+}
+
+// editConfigCmd suspends the TUI and opens the config file in $EDITOR,
+// creating a starter file first if there is none yet. The config watch
+// reloads whatever was saved.
+func editConfigCmd() (tea.Cmd, error) {
+	path := config.Path()
+	if err := config.EnsureFile(path); err != nil {
+		return nil, err
+	}
+	editor := strings.Fields(os.Getenv("EDITOR"))
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+	}
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return editorClosedMsg{err: err}
+	}), nil
+}
+
+// reloadConfig swaps in a freshly loaded config and tab list and reruns
+// the active tab. The active tab is found again by title when it survived
+// the reload; otherwise the old index is kept in range.
+func (m *Model) reloadConfig(cfg config.Config, tabs []config.Tab) tea.Cmd {
+	activeTitle := ""
+	if m.active < len(m.tabs) {
+		activeTitle = m.tabs[m.active].Title
+	}
+	m.cfg = cfg
+	m.tabs = tabs
+	monitor.SetLoopbackFallback(cfg.NetLoopbackFallback)
+	m.formatRate = rateFormatter(cfg.NetUnit)
+	m.cache = make(map[int]cmdResultMsg)
+	m.variants = make(map[int]int)
+	m.lastGood = make(map[int]string)
+	m.lastErr = make(map[int]error)
+	m.failures = make(map[int]int)
+	m.invalidateRuns()
+	m.latency = make(map[int][]float64)
+	m.derived = compileDerived(cfg.Derived)
+	m.derivedHistory = nil
+	if idx := tabIndexByTitle(m.tabs, activeTitle); idx != -1 {
+		m.active = idx
+	}
+	if m.active >= len(m.tabs) {
+		m.active = len(m.tabs) - 1
+	}
+	if m.active < 0 {
+		m.active = 0
+	}
+	cmd := m.onTabSelected()
+	m.setNotice(fmt.Sprintf("config reloaded (%d tabs)", len(m.tabs)))
+	return cmd
+}
+
+func tabIndexByTitle(tabs []config.Tab, title string) int {
+	for i, t := range tabs {
+		if t.Title == title {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+)
+
+func TestReloadConfigClampsActive(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{
+		{Title: "a", Cmd: []string{"echo", "a"}},
+		{Title: "b", Cmd: []string{"echo", "b"}},
+		{Title: "c", Cmd: []string{"echo", "c"}},
+	}
+	m.active = 2
+	m.cache[2] = cmdResultMsg{tab: 2, output: "old"}
+
+	cfg := config.Config{GlobalRefreshInterval: m.cfg.GlobalRefreshInterval, NetUnit: config.NetUnitBits}
+	tabs := []config.Tab{
+		{Title: "x", Cmd: []string{"echo", "x"}},
+		{Title: "y", Cmd: []string{"echo", "y"}, RefreshInterval: cfg.GlobalRefreshInterval},
+	}
+	cmd := m.reloadConfig(cfg, tabs)
+
+	if len(m.tabs) != 2 || m.tabs[0].Title != "x" {
+		t.Fatalf("tabs not replaced: %+v", m.tabs)
+	}
+	if m.active != 1 {
+		t.Errorf("active = %d, want clamped to 1", m.active)
+	}
+	if len(m.cache) != 0 {
+		t.Errorf("cache should be cleared on reload, has %d entries", len(m.cache))
+	}
+	if m.cfg.NetUnit != config.NetUnitBits {
+		t.Errorf("cfg not replaced")
+	}
+	if got := m.formatRate(1); got != "8Kbps" {
+		t.Errorf("formatRate not rebuilt for new net_unit, got %q", got)
+	}
+	if cmd == nil {
+		t.Error("expected the active tab to be rerun after reload")
+	}
+	if m.notice == "" || !time.Now().Before(m.noticeUntil) {
+		t.Error("expected a reload notice")
+	}
+}
+
+func TestReloadConfigKeepsActiveByTitle(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{
+		{Title: "a", Cmd: []string{"echo", "a"}},
+		{Title: "b", Cmd: []string{"echo", "b"}},
+	}
+	m.active = 1
+
+	m.reloadConfig(m.cfg, []config.Tab{
+		{Title: "new", Cmd: []string{"echo", "new"}},
+		{Title: "a", Cmd: []string{"echo", "a"}},
+		{Title: "b", Cmd: []string{"echo", "b"}},
+	})
+	if m.tabs[m.active].Title != "b" {
+		t.Errorf("active tab = %q, want b to stay selected", m.tabs[m.active].Title)
+	}
+}
@@ -0,0 +1,18 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+)
+
+// filterLines keeps only the lines of content matching re.
+func filterLines(content string, re *regexp.Regexp) string {
+	lines := strings.Split(content, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if re.MatchString(line) {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFilterLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		input    string
+		expected string
+	}{
+		{
+			name:     "anchored match",
+			pattern:  "^cpu",
+			input:    "cpu  all 1.0\nmem 2.0\ncpu0 3.0",
+			expected: "cpu  all 1.0\ncpu0 3.0",
+		},
+		{
+			name:     "no matches",
+			pattern:  "nginx",
+			input:    "sshd\ncron",
+			expected: "",
+		},
+		{
+			name:     "everything matches",
+			pattern:  ".",
+			input:    "a\nb",
+			expected: "a\nb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := filterLines(tt.input, regexp.MustCompile(tt.pattern))
+			if got != tt.expected {
+				t.Errorf("filterLines(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package ui
+
+import "strings"
+
+// splitHeader separates the first n lines of content from the rest so they
+// can stay pinned while the body scrolls. n <= 0 leaves content untouched.
+func splitHeader(content string, n int) (header, body string) {
+	if n <= 0 || content == "" {
+		return "", content
+	}
+	lines := strings.SplitN(content, "\n", n+1)
+	if len(lines) <= n {
+		return content, ""
+	}
+	return strings.Join(lines[:n], "\n"), lines[n]
+}
+
+func lineCount(s string) int {
+	if s == "" {
+		return 0
+	}
+	return strings.Count(s, "\n") + 1
+}
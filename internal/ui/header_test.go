@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestSplitHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		n          int
+		wantHeader string
+		wantBody   string
+	}{
+		{
+			name:       "no header",
+			content:    "a\nb\nc",
+			n:          0,
+			wantHeader: "",
+			wantBody:   "a\nb\nc",
+		},
+		{
+			name:       "single header line",
+			content:    "PID CPU\n1 0.5\n2 0.1",
+			n:          1,
+			wantHeader: "PID CPU",
+			wantBody:   "1 0.5\n2 0.1",
+		},
+		{
+			name:       "multi-line header",
+			content:    "Linux 6.1\n\nDevice tps\nsda 1.0",
+			n:          3,
+			wantHeader: "Linux 6.1\n\nDevice tps",
+			wantBody:   "sda 1.0",
+		},
+		{
+			name:       "header longer than content",
+			content:    "only\ntwo",
+			n:          5,
+			wantHeader: "only\ntwo",
+			wantBody:   "",
+		},
+		{
+			name:       "negative count",
+			content:    "a\nb",
+			n:          -1,
+			wantHeader: "",
+			wantBody:   "a\nb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, body := splitHeader(tt.content, tt.n)
+			if header != tt.wantHeader {
+				t.Errorf("header = %q, want %q", header, tt.wantHeader)
+			}
+			if body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// escapeRe matches a CSI escape sequence, or an OSC one such as a
+// hyperlink ended by BEL or ST, at the start of a string.
+var escapeRe = regexp.MustCompile(`^(?:\x1b\[[\d;?]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\))`)
+
+// highlightKeywords wraps every occurrence of the keywords in styles, the
+// first keyword in styles[0], the next in styles[1] and so on, reusing the
+// last style once they run out. Colors already in content are restored
+// after each highlight, and escape sequences are never matched.
+func highlightKeywords(content string, keywords []string, styles []lipgloss.Style) string {
+	if len(keywords) == 0 || len(styles) == 0 {
+		return content
+	}
+	type keyword struct {
+		word  string
+		style lipgloss.Style
+	}
+	var words []keyword
+	for i, w := range keywords {
+		if w != "" {
+			words = append(words, keyword{w, styles[min(i, len(styles)-1)]})
+		}
+	}
+	// Prefer the longest match so "ERRORS" wins over "ERROR".
+	sort.SliceStable(words, func(i, j int) bool { return len(words[i].word) > len(words[j].word) })
+
+	var b strings.Builder
+	var sgr string // color sequences active at the current position
+	for i := 0; i < len(content); {
+		if seq := escapeRe.FindString(content[i:]); seq != "" {
+			if strings.HasSuffix(seq, "m") {
+				if seq == "\x1b[m" || seq == "\x1b[0m" {
+					sgr = ""
+				} else {
+					sgr += seq
+				}
+			}
+			b.WriteString(seq)
+			i += len(seq)
+			continue
+		}
+		matched := false
+		for _, w := range words {
+			if strings.HasPrefix(content[i:], w.word) {
+				b.WriteString(w.style.Render(w.word))
+				b.WriteString(sgr)
+				i += len(w.word)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			b.WriteByte(content[i])
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestHighlightKeywords(t *testing.T) {
+	red := lipgloss.NewStyle().Transform(func(s string) string { return "<r>" + s + "</r>" })
+	yellow := lipgloss.NewStyle().Transform(func(s string) string { return "<y>" + s + "</y>" })
+	styles := []lipgloss.Style{red, yellow}
+
+	tests := []struct {
+		name     string
+		input    string
+		keywords []string
+		want     string
+	}{
+		{"none", "ERROR here", nil, "ERROR here"},
+		{"plain", "ERROR a\nWARN b", []string{"ERROR", "WARN"}, "<r>ERROR</r> a\n<y>WARN</y> b"},
+		{"extra keywords reuse last style", "WARN INFO", []string{"ERROR", "WARN", "INFO"}, "<y>WARN</y> <y>INFO</y>"},
+		{"longest first", "ERRORS", []string{"ERROR", "ERRORS"}, "<y>ERRORS</y>"},
+		{
+			"restores color",
+			"\x1b[32mok ERROR then WARN\x1b[0m done WARN",
+			[]string{"ERROR", "WARN"},
+			"\x1b[32mok <r>ERROR</r>\x1b[32m then <y>WARN</y>\x1b[32m\x1b[0m done <y>WARN</y>",
+		},
+		{"skips escapes", "\x1b[31mx", []string{"31"}, "\x1b[31mx"},
+		{
+			"skips OSC hyperlinks",
+			"\x1b]8;;https://x/ERROR\x1b\\link\x1b]8;;\x07 ERROR",
+			[]string{"ERROR"},
+			"\x1b]8;;https://x/ERROR\x1b\\link\x1b]8;;\x07 <r>ERROR</r>",
+		},
+		{"skips OSC titles", "\x1b]0;ERROR\x07ok", []string{"ERROR"}, "\x1b]0;ERROR\x07ok"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightKeywords(tt.input, tt.keywords, styles); got != tt.want {
+				t.Errorf("highlightKeywords(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineRunes is the longest line handed to the viewport. Longer lines,
+// such as minified JSON, make lipgloss rendering crawl.
+const maxLineRunes = 10000
+
+// breakHugeLines splits every line longer than max runes into chunks of
+// max runes. Lines within the limit are left untouched.
+func breakHugeLines(s string, max int) string {
+	if max <= 0 || len(s) <= max {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	changed := false
+	for i, line := range lines {
+		if utf8.RuneCountInString(line) <= max {
+			continue
+		}
+		var b strings.Builder
+		n := 0
+		for _, r := range line {
+			if n == max {
+				b.WriteByte('\n')
+				n = 0
+			}
+			b.WriteRune(r)
+			n++
+		}
+		lines[i] = b.String()
+		changed = true
+	}
+	if !changed {
+		return s
+	}
+	return strings.Join(lines, "\n")
+}
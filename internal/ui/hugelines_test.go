@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBreakHugeLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		max      int
+		expected string
+	}{
+		{"short", "abc\ndef", 3, "abc\ndef"},
+		{"long", "abcdefg\nhi", 3, "abc\ndef\ng\nhi"},
+		{"runes not bytes", "ééééé", 2, "éé\néé\né"},
+		{"disabled", "abcdef", 0, "abcdef"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := breakHugeLines(tt.input, tt.max); got != tt.expected {
+				t.Errorf("breakHugeLines(%q, %d) = %q, want %q", tt.input, tt.max, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBreakHugeLinesMegabyte(t *testing.T) {
+	line := strings.Repeat("x", 1<<20)
+	got := breakHugeLines(line, maxLineRunes)
+	lines := strings.Split(got, "\n")
+	if want := (1<<20 + maxLineRunes - 1) / maxLineRunes; len(lines) != want {
+		t.Fatalf("got %d lines, want %d", len(lines), want)
+	}
+	for i, l := range lines {
+		if len(l) > maxLineRunes {
+			t.Fatalf("line %d has %d runes, want at most %d", i, len(l), maxLineRunes)
+		}
+	}
+	if strings.ReplaceAll(got, "\n", "") != line {
+		t.Error("breaking lines lost content")
+	}
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/internal/spark"
+)
+
+// recordLatency appends how long a tab's command took to that tab's
+// history, keeping the same number of points as the metric sparklines.
+// Builtins report no duration and are skipped.
+func (m *Model) recordLatency(tab int, took time.Duration) {
+	if took <= 0 {
+		return
+	}
+	h := append(m.latency[tab], took.Seconds())
+	if len(h) > monitor.HistoryLength {
+		h = h[len(h)-monitor.HistoryLength:]
+	}
+	m.latency[tab] = h
+}
+
+// latencySpark renders the active tab's command latency history and the
+// latest duration for the content title, e.g. " .:=@ 1.2s".
+func (m Model) latencySpark() string {
+	h := m.latency[m.active]
+	if len(h) == 0 {
+		return ""
+	}
+	last := time.Duration(h[len(h)-1] * float64(time.Second))
+	return spark.Render(h, 0, monitor.Max(h), m.styles.Ramp) + " " + formatTook(last)
+}
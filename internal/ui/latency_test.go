@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+func TestLatencyAccumulatesPerTab(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "a", Cmd: []string{"echo"}}, {Title: "b", Cmd: []string{"echo"}}}
+
+	send := func(tab int, took time.Duration) {
+		updated, _ := m.Update(cmdResultMsg{tab: tab, output: "x", at: time.Now(), took: took})
+		next, ok := updated.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+	}
+	send(0, 100*time.Millisecond)
+	send(1, 2*time.Second)
+	send(0, 300*time.Millisecond)
+	send(0, 0) // builtins report no duration
+
+	if got := m.latency[0]; len(got) != 2 || got[0] != 0.1 || got[1] != 0.3 {
+		t.Errorf("tab 0 latency = %v, want [0.1 0.3]", got)
+	}
+	if got := m.latency[1]; len(got) != 1 || got[0] != 2 {
+		t.Errorf("tab 1 latency = %v, want [2]", got)
+	}
+	if spark := m.latencySpark(); !strings.HasSuffix(spark, " 0.3s") {
+		t.Errorf("latencySpark = %q, want the latest duration", spark)
+	}
+
+	for i := 0; i < monitor.HistoryLength+5; i++ {
+		send(1, time.Second)
+	}
+	if got := len(m.latency[1]); got != monitor.HistoryLength {
+		t.Errorf("latency history length = %d, want %d", got, monitor.HistoryLength)
+	}
+}
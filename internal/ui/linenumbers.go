@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// withLineNumbers prefixes each line with its right-aligned number rendered
+// in gutter. Numbers are padded to the widest one so the text stays aligned;
+// the lines themselves, including any ANSI colors, are left untouched.
+func withLineNumbers(content string, gutter lipgloss.Style) string {
+	if content == "" {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	width := len(strconv.Itoa(len(lines)))
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(gutter.Render(fmt.Sprintf("%*d ", width, i+1)))
+		b.WriteString(line)
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestWithLineNumbers(t *testing.T) {
+	plain := lipgloss.NewStyle()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "empty",
+			input:    "",
+			expected: "",
+		},
+		{
+			name:     "single digit",
+			input:    "a\nb",
+			expected: "1 a\n2 b",
+		},
+		{
+			name:     "colors preserved",
+			input:    "\x1b[31mred\x1b[0m\nplain",
+			expected: "1 \x1b[31mred\x1b[0m\n2 plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := withLineNumbers(tt.input, plain)
+			if got != tt.expected {
+				t.Errorf("withLineNumbers(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWithLineNumbersMultiDigit(t *testing.T) {
+	lines := make([]string, 12)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line%d", i+1)
+	}
+	got := strings.Split(withLineNumbers(strings.Join(lines, "\n"), lipgloss.NewStyle()), "\n")
+
+	if got[0] != " 1 line1" {
+		t.Errorf("first line = %q, want %q", got[0], " 1 line1")
+	}
+	if got[11] != "12 line12" {
+		t.Errorf("last line = %q, want %q", got[11], "12 line12")
+	}
+}
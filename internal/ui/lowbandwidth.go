@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// lowBandwidthFactor stretches every refresh interval in low-bandwidth
+	// mode, and lowBandwidthMinInterval is the shortest one it allows.
+	lowBandwidthFactor      = 3
+	lowBandwidthMinInterval = 10 * time.Second
+)
+
+// WithLowBandwidth returns a copy of the model tuned for slow links such as
+// SSH: longer refresh intervals, no spinner animation and no colors in
+// command output, so far fewer bytes are redrawn.
+func (m Model) WithLowBandwidth() Model {
+	m.lowBandwidth = true
+	m.stripColor = true
+	return m
+}
+
+// lowBandwidthInterval stretches a refresh interval for low-bandwidth mode.
+// Intervals of zero or less keep their meaning.
+func lowBandwidthInterval(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return max(d*lowBandwidthFactor, lowBandwidthMinInterval)
+}
+
+// spinnerCmd schedules the next spinner frame, or nothing in low-bandwidth
+// mode where the constant redraws would dominate the traffic.
+func (m Model) spinnerCmd() tea.Cmd {
+	if m.lowBandwidth {
+		return nil
+	}
+	return spinnerTick()
+}
+
+// spinnerFrame is the spinner glyph for the footer, empty when the spinner
+// is off.
+func (m Model) spinnerFrame() string {
+	if m.lowBandwidth {
+		return ""
+	}
+	return spinnerFrames[m.spinnerIdx]
+}
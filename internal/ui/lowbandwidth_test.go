@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+)
+
+func TestLowBandwidthStopsSpinner(t *testing.T) {
+	m := NewModel()
+	if _, cmd := m.Update(spinnerMsg(time.Now())); cmd == nil {
+		t.Fatal("spinner should keep ticking by default")
+	}
+
+	m = m.WithLowBandwidth()
+	next, cmd := m.Update(spinnerMsg(time.Now()))
+	if cmd != nil {
+		t.Error("spinner should stop ticking in low-bandwidth mode")
+	}
+	nm, ok := next.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if frame := nm.spinnerFrame(); frame != "" {
+		t.Errorf("spinnerFrame = %q, want empty", frame)
+	}
+	if !m.stripColor {
+		t.Error("low-bandwidth mode should strip colors")
+	}
+}
+
+func TestLowBandwidthHidesCountdown(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "a", Cmd: []string{"echo"}}}
+	m.lastTick = time.Now()
+	if m.countdownText(time.Now()) == "" {
+		t.Fatal("countdown should show by default")
+	}
+	if got := m.WithLowBandwidth().countdownText(time.Now()); got != "" {
+		t.Errorf("countdownText = %q, want none in low-bandwidth mode", got)
+	}
+}
+
+func TestLowBandwidthInterval(t *testing.T) {
+	tests := []struct {
+		in, want time.Duration
+	}{
+		{0, 0},
+		{time.Second, 10 * time.Second},
+		{5 * time.Second, 15 * time.Second},
+		{time.Minute, 3 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := lowBandwidthInterval(tt.in); got != tt.want {
+			t.Errorf("lowBandwidthInterval(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	m := NewModel()
+	want := lowBandwidthInterval(m.refreshInterval())
+	if got := m.WithLowBandwidth().refreshInterval(); got != want {
+		t.Errorf("refreshInterval = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/internal/spark"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// detailSeries is one metric in the history view.
+type detailSeries struct {
+	name   string
+	values []float64
+	min    float64
+	max    float64
+	format func(float64) string
+	// reason explains why the latest sample has no value, if it doesn't.
+	reason string
+}
+
+// renderMetricsDetail draws the full-screen history view: a tall chart per
+// metric with its scale on the left and now/min/avg/max above it.
+func renderMetricsDetail(m Model) string {
+	pct := func(v float64) string { return fmt.Sprintf("%0.0f%%", v) }
+	loadMax := monitor.Max(m.metrics.Load)
+	if loadMax < 2 {
+		loadMax = 2
+	}
+	netMax := monitor.Max(m.metrics.Net)
+	if netMax < 1 {
+		netMax = 1
+	}
+	series := []detailSeries{
+		{"CPU", m.metrics.CPU, 0, 100, pct, m.sample.CPUReason},
+		{"MEM", m.metrics.Mem, 0, 100, pct, m.sample.MemReason},
+		{"LOAD", m.metrics.Load, 0, loadMax, func(v float64) string { return fmt.Sprintf("%0.2f", v) }, m.sample.LoadReason},
+		{"NET", m.metrics.Net, 0, netMax, m.formatRate, m.sample.NetReason},
+	}
+
+	bold := lipgloss.NewStyle().Bold(true)
+	muted := lipgloss.NewStyle().Foreground(m.styles.Muted)
+	rows := []string{bold.Render("Metrics history") + "  " + muted.Render("(m to close)")}
+
+	// Each series takes a stats line and a blank separator besides its chart.
+	chartRows := (m.height-2)/len(series) - 2
+	if chartRows < 1 {
+		chartRows = 1
+	}
+	for _, s := range series {
+		rows = append(rows, "")
+		if len(s.values) == 0 {
+			na := "n/a"
+			if s.reason != "" {
+				na += " (" + s.reason + ")"
+			}
+			rows = append(rows, bold.Render(s.name)+"  "+muted.Render(na))
+			continue
+		}
+		lo, avg, hi := seriesStats(s.values)
+		now, _ := monitor.Latest(s.values)
+		rows = append(rows, fmt.Sprintf("%s  now %s  %s", bold.Render(s.name), s.format(now),
+			muted.Render(fmt.Sprintf("min %s  avg %s  max %s", s.format(lo), s.format(avg), s.format(hi)))))
+
+		if s.reason != "" {
+			rows = append(rows, muted.Render("latest sample unavailable: "+s.reason))
+		}
+		if s.name == "NET" && m.sample.OkNetTotal {
+			rows = append(rows, muted.Render("NET total: "+monitor.FormatBytes(m.sample.NetTotalBytes)+" since start"))
+		}
+
+		top, bottom := s.format(s.max), s.format(s.min)
+		axisWidth := max(lipgloss.Width(top), lipgloss.Width(bottom))
+		style := m.styles.Processing
+		if s.max == 100 {
+			style = m.levelStyle(now)
+		}
+		chart := renderChart(s.values, s.min, s.max, m.width-axisWidth-2, chartRows)
+		for i, line := range chart {
+			label := ""
+			switch i {
+			case 0:
+				label = top
+			case len(chart) - 1:
+				label = bottom
+			}
+			rows = append(rows, muted.Render(fmt.Sprintf("%*s |", axisWidth, label))+style.Render(line))
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// seriesStats returns the minimum, mean and maximum of values, skipping
+// gaps. values must hold at least one sample.
+func seriesStats(values []float64) (lo, avg, hi float64) {
+	lo, _ = monitor.Latest(values)
+	hi = lo
+	var sum float64
+	var n int
+	for _, v := range values {
+		if monitor.IsGap(v) {
+			continue
+		}
+		lo = min(lo, v)
+		hi = max(hi, v)
+		sum += v
+		n++
+	}
+	return lo, sum / float64(max(n, 1)), hi
+}
+
+// renderChart draws values as a bar chart height rows tall, stretching
+// each value across as many columns as fit in width and keeping only the
+// latest values when there are more than columns. Bars are '#' with the
+// sparkline ramp on top for the fractional row.
+func renderChart(values []float64, lo, hi float64, width, height int) []string {
+	if width < 1 || height < 1 || len(values) == 0 {
+		return nil
+	}
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+	cols := width / len(values)
+	lines := make([][]rune, height)
+	for i := range lines {
+		lines[i] = make([]rune, 0, width)
+	}
+	for _, v := range values {
+		fill := (v - lo) / (hi - lo) * float64(height)
+		for row := 0; row < height; row++ {
+			// row 0 is the top of the chart.
+			level := fill - float64(height-1-row)
+			r := ' '
+			switch {
+			case level >= 1:
+				r = '#'
+			case level > 0:
+				r = []rune(spark.Render([]float64{level}, 0, 1, spark.ASCII))[0]
+			}
+			for c := 0; c < cols; c++ {
+				lines[row] = append(lines[row], r)
+			}
+		}
+	}
+	out := make([]string, height)
+	for i, l := range lines {
+		out[i] = string(l)
+	}
+	return out
+}
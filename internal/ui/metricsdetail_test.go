@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRenderMetricsDetail(t *testing.T) {
+	m := NewModel()
+	m.width, m.height = 80, 40
+	m.metrics = monitor.MetricHistory{
+		CPU:  []float64{10, 50, 90},
+		Mem:  []float64{40, 42, 44},
+		Load: []float64{0.5, 1.5, 1.0},
+		Net:  []float64{100, 300, 200},
+	}
+	m.sample = monitor.MetricsSample{NetTotalBytes: 1.2 * 1024 * 1024 * 1024, OkNetTotal: true}
+
+	out := renderMetricsDetail(m)
+	for _, want := range []string{
+		"Metrics history",
+		"CPU  now 90%", "min 10%  avg 50%  max 90%",
+		"MEM  now 44%", "min 40%  avg 42%  max 44%",
+		"LOAD  now 1.00", "max 1.50",
+		"NET  now 200KiB/s",
+		"NET total: 1.2GiB since start",
+		"100% |", "  0% |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("detail view missing %q:\n%s", want, out)
+		}
+	}
+	if lines := strings.Count(out, "\n") + 1; lines > m.height {
+		t.Errorf("detail view has %d lines, want at most %d", lines, m.height)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if w := len([]rune(line)); w > m.width {
+			t.Errorf("line wider than %d: %q", m.width, line)
+		}
+	}
+}
+
+func TestRenderMetricsDetailEmpty(t *testing.T) {
+	m := NewModel()
+	m.width, m.height = 80, 24
+	if out := renderMetricsDetail(m); strings.Count(out, "n/a") != 4 {
+		t.Errorf("empty history should mark every metric n/a:\n%s", out)
+	}
+}
+
+func TestRenderMetricsDetailReasons(t *testing.T) {
+	m := NewModel()
+	m.width, m.height = 80, 40
+	m.metrics = monitor.MetricHistory{Load: []float64{1}}
+	m.sample = monitor.MetricsSample{CPUReason: "vmstat and mpstat not found", LoadReason: "uptime: parse error"}
+
+	out := renderMetricsDetail(m)
+	for _, want := range []string{
+		"CPU  n/a (vmstat and mpstat not found)",
+		"latest sample unavailable: uptime: parse error",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("detail view missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	got := renderChart([]float64{0, 50, 100}, 0, 100, 6, 2)
+	want := []string{"    ##", "  ####"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("renderChart = %q, want %q", got, want)
+	}
+}
+
+func TestMetricsDetailToggle(t *testing.T) {
+	m := NewModel()
+	m.width, m.height = 80, 24
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	m, ok := updated.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if !m.showDetail || !strings.Contains(m.View(), "Metrics history") {
+		t.Fatal("m should open the metrics history view")
+	}
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'m'}})
+	if m, ok = updated.(Model); !ok {
+		t.Fatal("Expected Model type")
+	}
+	if m.showDetail {
+		t.Error("m should close the metrics history view")
+	}
+}
@@ -1,9 +1,10 @@
 package ui
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -15,17 +16,33 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type tickMsg time.Time
 type spinnerMsg time.Time
 
 type cmdResultMsg struct {
+	tab int
+	// gen is the Model.runGen the run was started under.
+	gen    int
 	output string
+	stderr string
 	err    error
+	at     time.Time
+	// took is the command's wall-clock run time; zero for builtins.
+	took time.Duration
+	// usage is the child's own resource use, when it could be sampled.
+	usage   monitor.ProcUsage
+	okUsage bool
+	// overview holds the disks and processes sampled for the overview
+	// builtin.
+	overview monitor.OverviewSample
 }
 
 type metricsMsg struct {
+	// gen is the Model.metricsGen the sample was started under.
+	gen     int
 	metrics monitor.MetricsSample
 }
 
@@ -35,8 +52,15 @@ type systemMsg struct {
 
 const (
 	spinnerInterval = 200 * time.Millisecond
-	fixedRows       = 9
-	keyCtrlC        = "ctrl+c"
+	noticeDuration  = 2 * time.Second
+	baseFixedRows   = 9
+	// chromeRows is how many of baseFixedRows the metrics and system rows use.
+	chromeRows = 2
+	// borderRows is how many of baseFixedRows the content box border uses.
+	borderRows = 2
+	// footerRows is how many of baseFixedRows the wrapped footer may use.
+	footerRows = 3
+	keyCtrlC   = "ctrl+c"
 )
 
 var spinnerFrames = []string{"|", "/", "-", "\\"}
@@ -46,95 +70,343 @@ type Model struct {
 	active     int
 	viewport   viewport.Model
 	content    string
-	statusLine string
-	metrics    monitor.MetricHistory
+	header     string
+	headerRows int
+	lineNums   bool
+	// sortColumn orders the body lines by that column, counting from 1;
+	// 0 keeps the command's order. sortDesc reverses it.
+	sortColumn int
+	sortDesc   bool
+	// showChrome shows the metrics and system rows.
+	showChrome bool
+	// showDetail replaces the screen with the metrics history view.
+	showDetail bool
+	// alerting is whether the last sample crossed an alert threshold.
+	alerting bool
+	// lastTick is when the refresh tick last fired, for the countdown.
+	lastTick time.Time
+	// quitArmedUntil is when a first quit press under confirm_quit expires.
+	quitArmedUntil time.Time
+	// stripColor removes all ANSI styling from command output.
+	stripColor   bool
+	bodyLines    []string
+	selectedLine int
+	statusLine   string
+	stderrNote   string
+	// notice briefly replaces the status line, e.g. after a theme change.
+	notice      string
+	noticeUntil time.Time
+	metrics     monitor.MetricHistory
+	sample      monitor.MetricsSample
+	// alpha is the EWMA weight for new metric samples; 1 disables smoothing.
+	alpha      float64
 	system     monitor.SystemInfo
 	themeIndex int
 	spinnerIdx int
 	width      int
 	height     int
 	styles     theme.Styles
+	cfg        config.Config
+	formatRate func(float64) string
+	run        runner
+	// cache holds the latest result per tab index when prefetching.
+	cache map[int]cmdResultMsg
+	// variants holds the selected command index for tabs with cmds.
+	variants map[int]int
+	// inFlight marks tabs (or prefetchKey) with a run still pending.
+	inFlight map[int]bool
+	// runGen tags command runs and is bumped when a tab index stops
+	// meaning the command that was started, so late results are dropped.
+	runGen int
+	// metricsGen tags metrics samples and is bumped when the history is
+	// cleared, so a sample taken before the clear is dropped.
+	metricsGen int
+	// lastGood and lastErr track each tab's latest successful output and
+	// error so a failed refresh can keep showing the old output as stale.
+	lastGood map[int]string
+	lastErr  map[int]error
+	// failures counts each tab's consecutive failed runs for quiet_errors.
+	failures map[int]int
+	// latency holds each tab's recent command durations in seconds.
+	latency map[int][]float64
+	// derived are the custom metrics from [[derived]], with their history
+	// in derivedHistory at the same index.
+	derived        []derivedMetric
+	derivedHistory [][]float64
+	// ctx is cancelled on shutdown to abort in-flight commands.
+	ctx context.Context
+	// onSample, when set, receives every raw metrics sample, e.g. to
+	// export it to statsd.
+	onSample func(monitor.MetricsSample)
+	// lowBandwidth stretches refresh intervals and turns off the spinner
+	// for slow links.
+	lowBandwidth bool
+	// pager is the built-in pager, open when no external one is available.
+	pager pagerView
+	// overview is the overview builtin's latest disk and process sample.
+	overview monitor.OverviewSample
+	// loadOpts are the command-line options the config is loaded with.
+	loadOpts config.LoadOptions
 }
 
+// NewModel builds the model from the config file with no command-line
+// options.
 func NewModel() Model {
+	return NewModelWithOptions(config.LoadOptions{})
+}
+
+// NewModelWithOptions builds the model from the config file and opts, which
+// are kept for every later reload.
+func NewModelWithOptions(opts config.LoadOptions) Model {
 	vp := viewport.New(0, 0)
 	vp.SetContent("Loading...")
 
-	_, tabs := config.Load()
+	cfg, tabs := config.Load(opts)
+	monitor.SetLoopbackFallback(cfg.NetLoopbackFallback)
 
 	return Model{
-		tabs:       tabs,
-		active:     0,
-		viewport:   vp,
-		themeIndex: 0,
-		styles:     theme.BuildStyles(0),
+		tabs:         tabs,
+		active:       0,
+		viewport:     vp,
+		themeIndex:   0,
+		styles:       theme.BuildStyles(0),
+		cfg:          cfg,
+		loadOpts:     opts,
+		formatRate:   rateFormatter(cfg.NetUnit),
+		run:          ExecRunner,
+		cache:        make(map[int]cmdResultMsg),
+		inFlight:     make(map[int]bool),
+		variants:     make(map[int]int),
+		lastGood:     make(map[int]string),
+		lastErr:      make(map[int]error),
+		failures:     make(map[int]int),
+		latency:      make(map[int][]float64),
+		derived:      compileDerived(cfg.Derived),
+		selectedLine: noSelection,
+		showChrome:   true,
+		lastTick:     time.Now(),
+		alpha:        cfg.Smoothing(),
+		stripColor:   cfg.StripColor,
+		ctx:          context.Background(),
 	}
 }
 
-func (m Model) Init() tea.Cmd {
-	interval := m.tabs[m.active].RefreshInterval.Duration
-	if m.tabs[m.active].Disabled {
-		m.content = m.tabs[m.active].DisabledMsg
-		m.viewport.SetContent(m.content)
-		return tea.Batch(tick(interval), spinnerTick(), sampleMetricsCmd(), sampleSystemCmd())
+// WithContext returns a copy of the model whose commands and metric probes
+// are cancelled when ctx is done, so no child processes outlive it.
+func (m Model) WithContext(ctx context.Context) Model {
+	m.ctx = ctx
+	return m
+}
+
+// WithSampleHook returns a copy of the model that passes every raw metrics
+// sample to fn. fn runs off the UI goroutine.
+func (m Model) WithSampleHook(fn func(monitor.MetricsSample)) Model {
+	m.onSample = fn
+	return m
+}
+
+// gapMode maps missing_samples to how the metric history records a
+// failed sample.
+func gapMode(missing string) monitor.GapMode {
+	switch missing {
+	case config.MissingCarry:
+		return monitor.GapCarry
+	case config.MissingGap:
+		return monitor.GapMark
+	}
+	return monitor.GapSkip
+}
+
+func rateFormatter(unit string) func(float64) string {
+	if unit == config.NetUnitBits {
+		return monitor.FormatRateBits
 	}
-	return tea.Batch(runCommandCmd(m.tabs[m.active]), tick(interval), spinnerTick(), sampleMetricsCmd(), sampleSystemCmd())
+	return monitor.FormatRate
+}
+
+func (m Model) Init() tea.Cmd {
+	interval := m.refreshInterval()
+	return tea.Batch(m.refreshCmd(), tick(interval), m.spinnerCmd(), sampleMetricsCmd(m.ctx, m.metricsGen, m.onSample), sampleSystemCmd(m.ctx, m.formatRate, m.cfg.NetInterfaces), configWatchCmd(m.ctx, config.Path(), m.loadOpts))
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	interval := m.tabs[m.active].RefreshInterval.Duration
+	interval := m.refreshInterval()
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.pager.open {
+			return m.updatePager(msg)
+		}
 		if isQuitKey(msg) {
-			return m, tea.Quit
+			if m.shouldQuit(msg, time.Now()) {
+				return m, tea.Quit
+			}
+			return m, nil
 		}
 		switch msg.String() {
 		case keyCtrlC:
 			return m, tea.Quit
 		case "right", "l", "tab":
-			m.active = (m.active + 1) % len(m.tabs)
+			m.stepTab(1)
 			return m, m.onTabSelected()
 		case "left", "h", "shift+tab":
-			m.active--
-			if m.active < 0 {
-				m.active = len(m.tabs) - 1
-			}
+			m.stepTab(-1)
 			return m, m.onTabSelected()
 		case "t":
-			m.themeIndex = (m.themeIndex + 1) % len(theme.Themes)
+			m.themeIndex = theme.Normalize(m.themeIndex + 1)
 			m.styles = theme.BuildStyles(m.themeIndex)
+			m.setNotice("theme: " + theme.Themes[m.themeIndex].Name)
+			return m, nil
+		case "j":
+			m.moveSelection(1)
+			return m, nil
+		case "k":
+			m.moveSelection(-1)
+			return m, nil
+		case "enter":
+			return m, m.copySelectionCmd()
+		case "L":
+			m.lineNums = !m.lineNums
+			m.setContent(m.content, m.headerRows)
+			return m, nil
+		case "s":
+			m.stepSortColumn()
+			return m, nil
+		case "S":
+			m.toggleSortOrder()
+			return m, nil
+		case "n":
+			if len(m.tabs[m.active].Cmds) < 2 {
+				return m, nil
+			}
+			m.variants[m.active] = nextVariant(m.variants[m.active], len(m.tabs[m.active].Cmds))
+			m.invalidateRuns()
+			delete(m.cache, m.active)
+			delete(m.lastGood, m.active)
+			delete(m.lastErr, m.active)
+			delete(m.failures, m.active)
+			return m, m.onTabSelected()
+		case "[", "]":
+			delta := alphaStep
+			if msg.String() == "[" {
+				delta = -alphaStep
+			}
+			m.alpha = stepAlpha(m.alpha, delta)
+			m.setNotice(fmt.Sprintf("smoothing: alpha %.1f", m.alpha))
+			return m, nil
+		case "m":
+			m.showDetail = !m.showDetail
+			return m, nil
+		case "b":
+			m.showChrome = !m.showChrome
+			m.setContent(m.content, m.headerRows)
+			return m, nil
+		case "a":
+			m.stripColor = !m.stripColor
+			if m.stripColor {
+				m.setNotice("colors: off")
+			} else {
+				m.setNotice("colors: on")
+			}
+			return m, m.reapplyCmd()
+		case "e":
+			cmd, err := editConfigCmd()
+			if err != nil {
+				m.statusLine = fmt.Sprintf("error: %v", err)
+				return m, nil
+			}
+			return m, cmd
+		case "r":
+			return m, m.forceRefresh()
+		case "p":
+			return m, m.openPager(m.content)
+		case "C":
+			return m, m.openPager(toCSV(m.content))
+		case "c":
+			m.metrics = monitor.MetricHistory{}
+			m.derivedHistory = nil
+			m.metricsGen++
+			monitor.ResetNetBaseline()
 			return m, nil
 		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.viewport.Width = clampMin(msg.Width-2, 0)
-		m.viewport.Height = clampMin(msg.Height-fixedRows, 0)
-		m.viewport.SetContent(m.content)
-	case tickMsg:
-		if m.tabs[m.active].Disabled {
-			return m, tea.Batch(tick(interval), sampleMetricsCmd(), sampleSystemCmd())
+		m.setContent(m.content, m.headerRows)
+		m.refreshOverview()
+		if m.pager.open {
+			m.pager.viewport.Width = m.width
+			m.pager.viewport.Height = m.pagerHeight()
 		}
-		return m, tea.Batch(runCommandCmd(m.tabs[m.active]), tick(interval), sampleMetricsCmd(), sampleSystemCmd())
+	case tickMsg:
+		m.lastTick = time.Now()
+		return m, tea.Batch(m.startRefresh(), tick(interval), sampleMetricsCmd(m.ctx, m.metricsGen, m.onSample), sampleSystemCmd(m.ctx, m.formatRate, m.cfg.NetInterfaces))
 	case spinnerMsg:
 		m.spinnerIdx = (m.spinnerIdx + 1) % len(spinnerFrames)
-		return m, spinnerTick()
+		return m, m.spinnerCmd()
 	case cmdResultMsg:
-		m.content = sanitizeOutput(strings.TrimSpace(msg.output))
-		if m.content == "" {
-			m.content = "(no output)"
+		if msg.gen != m.runGen {
+			// Started before a reload or variant switch; its tab index
+			// may now name a different command.
+			return m, nil
+		}
+		delete(m.inFlight, msg.tab)
+		if msg.tab >= len(m.tabs) {
+			return m, nil
 		}
-		m.viewport.SetContent(m.content)
+		m.recordLatency(msg.tab, msg.took)
+		m.countRun(msg)
+		if m.cfg.Prefetch || m.tabs[msg.tab].Static || m.tabs[msg.tab].CacheTTL.Duration > 0 {
+			m.cache[msg.tab] = msg
+		}
+		if msg.tab == m.active {
+			m.applyResult(msg)
+		}
+	case prefetchMsg:
+		if msg.gen != m.runGen {
+			return m, nil
+		}
+		delete(m.inFlight, prefetchKey)
+		for _, res := range msg.results {
+			m.recordLatency(res.tab, res.took)
+			m.countRun(res)
+			m.cache[res.tab] = res
+		}
+		if res, ok := m.cache[m.active]; ok {
+			m.applyResult(res)
+		}
+	case editorClosedMsg:
+		// A saved change is picked up by the config watch, which would
+		// otherwise reload it a second time.
 		if msg.err != nil {
-			m.statusLine = fmt.Sprintf("error: %v", msg.err)
-		} else {
-			m.statusLine = fmt.Sprintf("updated %s (every %s)", time.Now().Format("15:04:05"), interval)
+			m.statusLine = fmt.Sprintf("error: editor: %v", msg.err)
+		}
+		return m, nil
+	case configReloadedMsg:
+		return m, tea.Batch(m.reloadConfig(msg.cfg, msg.tabs), configWatchCmd(m.ctx, config.Path(), m.loadOpts))
+	case pagerClosedMsg:
+		os.Remove(msg.path)
+		if msg.err != nil {
+			m.statusLine = fmt.Sprintf("error: pager: %v", msg.err)
 		}
+		return m, nil
+	case copiedMsg:
+		m.statusLine = fmt.Sprintf("copied line %d", msg.line)
+		return m, nil
 	case metricsMsg:
-		m.metrics = monitor.UpdateHistory(m.metrics, msg.metrics)
+		if msg.gen != m.metricsGen {
+			// Sampled before the history was cleared.
+			return m, nil
+		}
+		sample := smoothSample(m.metrics, msg.metrics, m.alpha)
+		m.metrics = monitor.UpdateHistory(m.metrics, sample, gapMode(m.cfg.MissingSamples))
+		m.sample = sample
+		m.updateDerived(sample)
+		m.refreshOverview()
+		return m, m.checkAlert()
 	case systemMsg:
 		m.system = msg.info
+		m.refreshOverview()
 	}
 
 	var cmd tea.Cmd
@@ -143,167 +415,387 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
+	if m.pager.open {
+		return m.renderPager()
+	}
 	header := m.renderTabs(m.tabs, m.active, m.width)
 	metricsRow := m.renderMetricsRow(m.metrics, m.width)
 	systemRow := m.renderSystemRow(m.system, m.width)
-	title := m.renderContentTitle(m.tabs[m.active].Title, m.width)
-	content := m.styles.ContentBox.Width(m.width).Render(m.viewport.View())
-	footer := m.renderFooter(m.statusLine, spinnerFrames[m.spinnerIdx], m.width)
-
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		header,
-		metricsRow,
-		systemRow,
-		title,
-		content,
-		footer,
-	)
-}
-
-func (m Model) onTabSelected() tea.Cmd {
+	titleText := m.tabTitle()
+	if latency := m.latencySpark(); latency != "" {
+		titleText += "  " + latency
+	}
+	title := m.renderContentTitle(titleText, m.staleErr(), m.width)
+	body := m.viewport.View()
+	if m.header != "" {
+		header := lipgloss.NewStyle().Width(m.viewport.Width).MaxWidth(m.viewport.Width).Render(m.header)
+		body = lipgloss.JoinVertical(lipgloss.Left, header, body)
+	}
+	box := m.contentBoxStyle()
+	content := box.Width(clampMin(m.width-box.GetHorizontalBorderSize(), 0)).Render(body)
+	status := m.statusLine
+	if m.notice != "" && time.Now().Before(m.noticeUntil) {
+		status = m.notice
+	} else if m.stderrNote != "" {
+		status += "  " + m.renderStderrNote(m.stderrNote)
+	}
+	if smoothing := m.smoothingText(); smoothing != "" {
+		status += "  " + smoothing
+	}
+	if countdown := m.countdownText(time.Now()); countdown != "" {
+		status += "  " + countdown
+	}
+	footer := m.renderFooter(status, m.spinnerFrame(), m.width)
+
+	if m.showDetail {
+		detail := strings.Split(renderMetricsDetail(m), "\n")
+		if room := m.height - lipgloss.Height(footer); room > 0 && len(detail) > room {
+			detail = detail[:room]
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, strings.Join(detail, "\n"), footer)
+	}
+
+	chrome := []string{header}
+	if m.showChrome {
+		// When hidden, sampling continues; the rows are just not drawn.
+		chrome = append(chrome, metricsRow, systemRow)
+	}
+
+	// Both layouts stack the same rows, so fixedRows holds either way.
+	var rows []string
+	if m.cfg.Layout == config.LayoutBottom {
+		rows = append([]string{title, content}, chrome...)
+		rows = append(rows, footer)
+	} else {
+		rows = append(chrome, title, content, footer)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+func (m *Model) onTabSelected() tea.Cmd {
+	m.selectedLine = noSelection
+	m.sortColumn, m.sortDesc = 0, false
 	if m.tabs[m.active].Disabled {
-		m.content = m.tabs[m.active].DisabledMsg
-		m.viewport.SetContent(m.content)
+		m.setContent(m.tabs[m.active].DisabledMsg, 0)
 		m.statusLine = "disabled"
 		return nil
 	}
-	m.content = "Loading..."
-	m.viewport.SetContent(m.content)
-	return runCommandCmd(m.tabs[m.active])
+	if res, ok := m.cache[m.active]; ok && (m.cfg.Prefetch || m.tabs[m.active].Static || m.cachedFresh(m.active, time.Now())) {
+		m.applyResult(res)
+		return nil
+	}
+	if m.cfg.ShowLoadingPlaceholder() {
+		m.setContent("Loading...", 0)
+	} else if good, ok := m.lastGood[m.active]; ok {
+		// Otherwise keep what is on screen, preferring this tab's last
+		// output, until the new run lands.
+		m.setContent(good, m.tabs[m.active].HeaderLines)
+	}
+	if m.inFlight[m.active] {
+		// The pending run's result will land here.
+		return nil
+	}
+	m.inFlight[m.active] = true
+	return runCommandCmd(m.ctx, m.runGen, m.active, m.tabAt(m.active), m.run)
 }
 
-func tick(d time.Duration) tea.Cmd {
-	return tea.Tick(d, func(t time.Time) tea.Msg { return tickMsg(t) })
+// startRefresh is refreshCmd guarded against overlap: while the previous
+// run for the active tab, or the previous prefetch, is still pending, it
+// starts nothing and leaves the next tick to try again.
+func (m *Model) startRefresh() tea.Cmd {
+	key := m.active
+	if m.cfg.Prefetch {
+		key = prefetchKey
+	}
+	if m.inFlight[key] {
+		return nil
+	}
+	cmd := m.refreshCmd()
+	if cmd != nil {
+		m.inFlight[key] = true
+	}
+	return cmd
 }
 
-func spinnerTick() tea.Cmd {
-	return tea.Tick(spinnerInterval, func(t time.Time) tea.Msg { return spinnerMsg(t) })
+// reapplyCmd re-renders the active tab after a display setting changes,
+// from the cached result when there is one, otherwise by running it again.
+func (m *Model) reapplyCmd() tea.Cmd {
+	if res, ok := m.cache[m.active]; ok {
+		m.applyResult(res)
+		return nil
+	}
+	return m.startRefresh()
 }
 
-func sampleMetricsCmd() tea.Cmd {
-	return func() tea.Msg {
-		return metricsMsg{metrics: monitor.SampleMetrics()}
+// fixedRows is the number of rows around the viewport, less the metrics
+// and system rows when they are hidden.
+func (m Model) fixedRows() int {
+	rows := baseFixedRows - borderRows + m.contentBoxStyle().GetVerticalBorderSize()
+	if m.showChrome {
+		return rows
 	}
+	return rows - chromeRows
 }
 
-func sampleSystemCmd() tea.Cmd {
-	return func() tea.Msg {
-		return systemMsg{info: monitor.SampleSystem()}
+// tabAt returns tab i with Cmd set to its selected variant.
+func (m Model) tabAt(i int) config.Tab {
+	t := m.tabs[i]
+	if v := m.variants[i]; v > 0 && v < len(t.Cmds) {
+		t.Cmd = t.Cmds[v]
 	}
+	return t
 }
 
-func runCommandCmd(t config.Tab) tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
-		defer cancel()
+func (m Model) runnableTabs() []config.Tab {
+	tabs := make([]config.Tab, len(m.tabs))
+	for i := range m.tabs {
+		tabs[i] = m.tabAt(i)
+	}
+	return tabs
+}
 
-		cmd := exec.CommandContext(ctx, t.Cmd[0], t.Cmd[1:]...)
-		var out bytes.Buffer
-		cmd.Stdout = &out
-		cmd.Stderr = &out
+// tabTitle is the active tab's title, naming the selected variant when the
+// tab has several commands.
+func (m Model) tabTitle() string {
+	t := m.tabAt(m.active)
+	title := expandTitle(t.Title, m.metrics, m.formatRate)
+	if len(t.Cmds) < 2 {
+		return title
+	}
+	return fmt.Sprintf("%s [%d/%d: %s]", title, m.variants[m.active]+1, len(t.Cmds), strings.Join(t.Cmd, " "))
+}
 
-		err := cmd.Run()
-		return cmdResultMsg{output: out.String(), err: err}
+// nextVariant advances a variant index, wrapping after the last of count.
+func nextVariant(cur, count int) int {
+	if count <= 0 {
+		return 0
 	}
+	return (cur + 1) % count
 }
 
-// Rendering helpers
+// setNotice shows a transient message in place of the status line until
+// it expires or the next command result arrives.
+func (m *Model) setNotice(msg string) {
+	m.notice = msg
+	m.noticeUntil = time.Now().Add(noticeDuration)
+}
 
-// renderMetricsRow renders the top row of sparklines and current values
-func (m Model) renderMetricsRow(history monitor.MetricHistory, width int) string {
-	if width <= 0 {
-		return ""
+// refreshInterval is the tick period: the active tab's interval, or the
+// global one when prefetching refreshes every tab at once.
+func (m Model) refreshInterval() time.Duration {
+	d := m.tabs[m.active].RefreshInterval.Duration
+	if m.cfg.Prefetch {
+		d = m.cfg.GlobalRefreshInterval.Duration
 	}
+	if m.lowBandwidth {
+		return lowBandwidthInterval(d)
+	}
+	return d
+}
 
-	// Helper to render a single metric block with color
-	renderBlock := func(label string, valStr string, data []float64, min, max float64, isPercent bool) string {
-		// Determine color based on latest value
-		var color lipgloss.Style
-		if len(data) > 0 {
-			last := data[len(data)-1]
-			// Normalize value for color mapping
-			param := last
-			if !isPercent {
-				// efficient approximation for load/net: reasonable max
-				// load: max 4.0 (green), 8.0 (yellow), >8.0 (red)
-				// net: max 1MB/s (green), 10MB/s (yellow), >10MB/s (red)
-				// This is heuristic, percent is easier
-				if max > 0 {
-					param = (last / max) * 100
-				}
-			}
+// refreshCmd re-runs the active tab, or every enabled tab when prefetching.
+// Static tabs only run until they have a result.
+func (m Model) refreshCmd() tea.Cmd {
+	if m.cfg.Prefetch {
+		return prefetchCmd(m.ctx, m.runGen, m.runnableTabs(), m.cache, m.run)
+	}
+	if m.tabs[m.active].Disabled {
+		return nil
+	}
+	if _, ok := m.cache[m.active]; ok && m.tabs[m.active].Static {
+		return nil
+	}
+	if m.cachedFresh(m.active, time.Now()) {
+		return nil
+	}
+	return runCommandCmd(m.ctx, m.runGen, m.active, m.tabAt(m.active), m.run)
+}
 
-			if param < 50 {
-				color = m.styles.Green
-			} else if param < 80 {
-				color = m.styles.Yellow
-			} else {
-				color = m.styles.Red
-			}
+// invalidateRuns drops every pending run: their results will be ignored
+// and the tabs are free to start new ones.
+func (m *Model) invalidateRuns() {
+	m.runGen++
+	m.inFlight = make(map[int]bool)
+}
+
+// applyResult renders a command result for the active tab and updates the
+// status line.
+func (m *Model) applyResult(msg cmdResultMsg) {
+	interval := m.refreshInterval()
+	if m.tabs[m.active].Builtin == config.BuiltinOverview {
+		m.overview = msg.overview
+		m.refreshOverview()
+		m.notice = ""
+		m.stderrNote = ""
+		m.statusLine = fmt.Sprintf("updated %s (every %s)", time.Now().Format(m.cfg.StatusTimeFormat()), interval)
+		return
+	}
+	if m.quiet(msg) {
+		return
+	}
+	stderr := strings.TrimSpace(msg.stderr)
+	output := msg.output
+	if strings.TrimSpace(output) == "" && msg.err != nil {
+		output = stderr
+	}
+	if looksBinary(output) {
+		output = imageHiddenMsg
+	}
+	content := breakHugeLines(sanitizeOutput(strings.TrimSpace(output)), maxLineRunes)
+	if m.stripColor {
+		content = stripAllANSI(content)
+	}
+	if m.cfg.NormalizeWhitespace {
+		content = normalizeWhitespace(content)
+	}
+	if re := m.tabs[m.active].FilterRe; re != nil {
+		content = filterLines(content, re)
+	}
+	var transformErr error
+	if steps := m.tabs[m.active].Transform; len(steps) > 0 {
+		content, transformErr = applyTransforms(content, steps)
+	}
+	if words := m.tabs[m.active].Highlight; len(words) > 0 && !m.stripColor {
+		content = highlightKeywords(content, words, []lipgloss.Style{m.styles.Red, m.styles.Yellow})
+	}
+	if content == "" {
+		content = "(no output)"
+	}
+	if msg.err != nil {
+		m.lastErr[m.active] = msg.err
+		// Keep showing the last good output; the title marks it stale.
+		if good, ok := m.lastGood[m.active]; ok {
+			content = good
+		}
+	} else {
+		delete(m.lastErr, m.active)
+		m.lastGood[m.active] = content
+	}
+	m.setContent(content, m.tabs[m.active].HeaderLines)
+	m.notice = ""
+	m.stderrNote = ""
+	if msg.err != nil {
+		if code, ok := exitCode(msg.err); ok {
+			m.statusLine = fmt.Sprintf("exit %d in %s", code, formatTook(msg.took))
 		} else {
-			color = m.styles.Processing
+			m.statusLine = fmt.Sprintf("error: %v", msg.err)
+		}
+		if stderr != "" {
+			m.statusLine += ": " + firstLine(stderr)
+		}
+	} else if transformErr != nil {
+		m.statusLine = fmt.Sprintf("error: %v", transformErr)
+	} else {
+		at := msg.at
+		if at.IsZero() {
+			at = time.Now()
+		}
+		if m.tabs[m.active].Static {
+			m.statusLine = fmt.Sprintf("updated %s (static)", at.Format(m.cfg.StatusTimeFormat()))
+		} else {
+			m.statusLine = fmt.Sprintf("updated %s (every %s)", at.Format(m.cfg.StatusTimeFormat()), interval)
+		}
+		if m.tabs[m.active].Builtin == "" {
+			took := formatTook(msg.took)
+			if msg.okUsage {
+				took += " " + formatUsage(msg.usage)
+			}
+			m.statusLine = fmt.Sprintf("exit 0 in %s, %s", took, m.statusLine)
+		}
+		if stderr != "" {
+			m.stderrNote = "stderr: " + firstLine(stderr)
 		}
-
-		sl := sparkline(data, min, max)
-		// Colorize the sparkline and the value
-		return fmt.Sprintf("%s %s %s", label, color.Render(valStr), color.Render(sl))
 	}
-
-	var blocks []string
-
-	// CPU
-	if len(history.CPU) > 0 {
-		val := history.CPU[len(history.CPU)-1]
-		blocks = append(blocks, renderBlock("CPU", fmt.Sprintf("%0.0f%%", val), history.CPU, 0, 100, true))
+	if warning := m.tabs[m.active].TTYWarning; warning != "" && m.stderrNote == "" {
+		m.stderrNote = warning
 	}
+}
 
-	// MEM
-	if len(history.Mem) > 0 {
-		val := history.Mem[len(history.Mem)-1]
-		blocks = append(blocks, renderBlock("MEM", fmt.Sprintf("%0.0f%%", val), history.Mem, 0, 100, true))
+// setContent shows content in the viewport, pinning its first headerLines
+// lines above the scrolling region, and refits the viewport to the window.
+func (m *Model) setContent(content string, headerLines int) {
+	m.content = content
+	m.headerRows = headerLines
+	header, body := splitHeader(content, headerLines)
+	body = sortByColumn(body, m.sortColumn, m.sortDesc)
+	m.header = header
+	m.viewport.Width = clampMin(m.width-m.contentBoxStyle().GetHorizontalFrameSize(), 0)
+	m.viewport.Height = clampMin(m.height-m.fixedRows()-lineCount(header), 0)
+	m.bodyLines = strings.Split(body, "\n")
+	if m.selectedLine >= len(m.bodyLines) {
+		m.selectedLine = len(m.bodyLines) - 1
+	}
+	if m.selectedLine != noSelection {
+		lines := make([]string, len(m.bodyLines))
+		copy(lines, m.bodyLines)
+		selected := lipgloss.NewStyle().Foreground(m.styles.Background).Background(m.styles.Accent)
+		lines[m.selectedLine] = selected.Render(ansi.Strip(lines[m.selectedLine]))
+		body = strings.Join(lines, "\n")
 	}
+	if m.lineNums {
+		body = withLineNumbers(body, lipgloss.NewStyle().Foreground(m.styles.Muted))
+	}
+	if m.cfg.CenterContent {
+		body = centerBody(body, m.viewport.Width, m.viewport.Height)
+	}
+	m.viewport.SetContent(body)
+}
 
-	// LOAD (heuristic color: <1.0 green, <high yellow, >high red)
-	if len(history.Load) > 0 {
-		val := history.Load[len(history.Load)-1]
-		max := maxFloat(history.Load)
-		if max < 2.0 {
-			max = 2.0
-		} // Minimum scale for load
+func tick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
 
-		// Custom logic for load color
-		var color lipgloss.Style
-		if val < 1.0 {
-			color = m.styles.Green
-		} else if val < 4.0 {
-			color = m.styles.Yellow
-		} else {
-			color = m.styles.Red
+func spinnerTick() tea.Cmd {
+	return tea.Tick(spinnerInterval, func(t time.Time) tea.Msg { return spinnerMsg(t) })
+}
+
+func sampleMetricsCmd(ctx context.Context, gen int, onSample func(monitor.MetricsSample)) tea.Cmd {
+	return func() tea.Msg {
+		sample := monitor.SampleMetrics(ctx)
+		if onSample != nil {
+			onSample(sample)
 		}
+		return metricsMsg{gen: gen, metrics: sample}
+	}
+}
 
-		sl := sparkline(history.Load, 0, max)
-		blocks = append(blocks, fmt.Sprintf("LOAD %s %s", color.Render(fmt.Sprintf("%0.2f", val)), color.Render(sl)))
+func sampleSystemCmd(ctx context.Context, formatRate func(float64) string, ifaces []string) tea.Cmd {
+	return func() tea.Msg {
+		return systemMsg{info: monitor.SampleSystem(ctx, formatRate, ifaces)}
 	}
+}
 
-	// NET
-	if len(history.Net) > 0 {
-		val := history.Net[len(history.Net)-1]
-		max := maxFloat(history.Net)
-		if max < 1 {
-			max = 1
-		}
-		blocks = append(blocks, renderBlock("NET", monitor.FormatRate(val), history.Net, 0, max, false))
+func runCommandCmd(ctx context.Context, gen, idx int, t config.Tab, run runner) tea.Cmd {
+	return func() tea.Msg {
+		res := runTab(ctx, idx, t, run)
+		res.gen = gen
+		return res
 	}
+}
 
-	if len(blocks) == 0 {
-		return m.styles.Summary.Width(width).Render("Waiting for metrics...")
+// runTab runs a single tab synchronously with the per-command timeout.
+// Cancelling parent kills the command.
+func runTab(parent context.Context, idx int, t config.Tab, run runner) cmdResultMsg {
+	if t.Builtin == config.BuiltinAbout {
+		return cmdResultMsg{tab: idx, output: monitor.SystemSummaryText(parent), at: time.Now()}
 	}
+	if t.Builtin == config.BuiltinOverview {
+		// Rendered from the sampled metrics in applyResult; only the disks
+		// and processes are sampled here.
+		return cmdResultMsg{tab: idx, at: time.Now(), overview: monitor.SampleOverview(parent, overviewMounts(t), overviewTopProcs)}
+	}
+	ctx, cancel := context.WithTimeout(parent, 4*time.Second)
+	defer cancel()
+	ctx, child := withChildUsage(withOutputLimit(ctx, t.MaxOutputBytes))
 
-	row := strings.Join(blocks, "   ")
-	return m.styles.Summary.Width(width).Render(row)
+	start := time.Now()
+	stdout, stderr, err := run(ctx, t.Cmd)
+	usage, okUsage := child.get()
+	return cmdResultMsg{tab: idx, output: stdout, stderr: stderr, err: err, at: time.Now(), took: time.Since(start), usage: usage, okUsage: okUsage}
 }
 
+// Rendering helpers
+
 func (m Model) renderTabs(tabs []config.Tab, active, width int) string {
 	if width <= 0 {
 		return ""
@@ -311,13 +803,14 @@ func (m Model) renderTabs(tabs []config.Tab, active, width int) string {
 	rendered := make([]string, 0, len(tabs))
 	renderedWidths := make([]int, 0, len(tabs))
 	for i, t := range tabs {
+		title := expandTitle(t.Title, m.metrics, m.formatRate)
 		var cell string
 		if i == active {
-			cell = m.styles.ActiveTab.Render(" " + t.Title + " ")
+			cell = m.styles.ActiveTab.Render(" " + title + " ")
 		} else if t.Disabled {
-			cell = m.styles.DisabledTab.Render(" " + t.Title + " ")
+			cell = m.styles.DisabledTab.Render(" " + title + " ")
 		} else {
-			cell = m.styles.InactiveTab.Render(" " + t.Title + " ")
+			cell = m.styles.InactiveTab.Render(" " + title + " ")
 		}
 		rendered = append(rendered, cell)
 		renderedWidths = append(renderedWidths, lipgloss.Width(cell))
@@ -329,20 +822,38 @@ func (m Model) renderTabs(tabs []config.Tab, active, width int) string {
 	}
 	if total <= width {
 		row := lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
-		return m.styles.Header.Width(width).Render(row)
+		return m.styles.Header.Width(width).Align(tabAlignPosition(m.cfg.TabAlign)).Render(row)
 	}
 
+	// Pinned tabs keep their place at the edges, so pack the window around
+	// the active tab into whatever width they leave.
+	markerWidth := lipgloss.Width(m.styles.Overflow.Render(" … "))
+	pinned := make([]bool, len(tabs))
+	reserved := 0
+	for i, t := range tabs {
+		if t.Pinned && i != active {
+			pinned[i] = true
+			reserved += renderedWidths[i]
+		}
+	}
+	if renderedWidths[active]+reserved+2*markerWidth > width {
+		// Too many pins to fit; fall back to plain overflow.
+		pinned = make([]bool, len(tabs))
+		reserved = 0
+	}
+	avail := width - reserved
+
 	left := active
 	right := active
 	used := renderedWidths[active]
 	for {
 		grew := false
-		if left > 0 && used+renderedWidths[left-1] <= width {
+		if left > 0 && used+renderedWidths[left-1] <= avail {
 			left--
 			used += renderedWidths[left]
 			grew = true
 		}
-		if right < len(tabs)-1 && used+renderedWidths[right+1] <= width {
+		if right < len(tabs)-1 && used+renderedWidths[right+1] <= avail {
 			right++
 			used += renderedWidths[right]
 			grew = true
@@ -352,17 +863,26 @@ func (m Model) renderTabs(tabs []config.Tab, active, width int) string {
 		}
 	}
 
-	leftOverflow := left > 0
-	rightOverflow := right < len(tabs)-1
+	// A side overflows when it hides a tab that is not pinned.
+	hides := func(from, to int) bool {
+		for i := from; i < to; i++ {
+			if !pinned[i] {
+				return true
+			}
+		}
+		return false
+	}
+	leftOverflow := hides(0, left)
+	rightOverflow := hides(right+1, len(tabs))
 	overflowWidth := 0
 	if leftOverflow {
-		overflowWidth += lipgloss.Width(m.styles.Overflow.Render(" … "))
+		overflowWidth += markerWidth
 	}
 	if rightOverflow {
-		overflowWidth += lipgloss.Width(m.styles.Overflow.Render(" … "))
+		overflowWidth += markerWidth
 	}
 
-	for used+overflowWidth > width && (left < active || right > active) {
+	for used+overflowWidth > avail && (left < active || right > active) {
 		if right > active && used+overflowWidth-renderedWidths[right] >= 0 {
 			used -= renderedWidths[right]
 			right--
@@ -372,18 +892,23 @@ func (m Model) renderTabs(tabs []config.Tab, active, width int) string {
 		} else {
 			break
 		}
-		leftOverflow = left > 0
-		rightOverflow = right < len(tabs)-1
+		leftOverflow = hides(0, left)
+		rightOverflow = hides(right+1, len(tabs))
 		overflowWidth = 0
 		if leftOverflow {
-			overflowWidth += lipgloss.Width(m.styles.Overflow.Render(" … "))
+			overflowWidth += markerWidth
 		}
 		if rightOverflow {
-			overflowWidth += lipgloss.Width(m.styles.Overflow.Render(" … "))
+			overflowWidth += markerWidth
 		}
 	}
 
-	parts := make([]string, 0, (right-left)+3)
+	parts := make([]string, 0, len(tabs)+2)
+	for i := 0; i < left; i++ {
+		if pinned[i] {
+			parts = append(parts, rendered[i])
+		}
+	}
 	if leftOverflow {
 		parts = append(parts, m.styles.Overflow.Render(" … "))
 	}
@@ -393,8 +918,24 @@ func (m Model) renderTabs(tabs []config.Tab, active, width int) string {
 	if rightOverflow {
 		parts = append(parts, m.styles.Overflow.Render(" … "))
 	}
+	for i := right + 1; i < len(tabs); i++ {
+		if pinned[i] {
+			parts = append(parts, rendered[i])
+		}
+	}
 	row := lipgloss.JoinHorizontal(lipgloss.Top, parts...)
-	return m.styles.Header.Width(width).Render(row)
+	return m.styles.Header.Width(width).Align(tabAlignPosition(m.cfg.TabAlign)).Render(row)
+}
+
+// tabAlignPosition maps tab_align onto a lipgloss alignment.
+func tabAlignPosition(align string) lipgloss.Position {
+	switch align {
+	case config.TabAlignCenter:
+		return lipgloss.Center
+	case config.TabAlignRight:
+		return lipgloss.Right
+	}
+	return lipgloss.Left
 }
 
 func (m Model) renderSystemRow(info monitor.SystemInfo, width int) string {
@@ -409,62 +950,115 @@ func (m Model) renderSystemRow(info monitor.SystemInfo, width int) string {
 	if info.Net != "" {
 		parts = append(parts, info.Net)
 	}
-	if info.Uptime != "" {
+	if m.cfg.UptimeDetail && info.UptimeDetail != "" {
+		parts = append(parts, info.UptimeDetail)
+	} else if info.Uptime != "" {
 		parts = append(parts, info.Uptime)
 	}
+	if info.Cores != "" {
+		parts = append(parts, info.Cores)
+	}
+	if info.OS != "" {
+		parts = append(parts, info.OS)
+	}
 
 	if len(parts) == 0 {
 		return ""
 	}
 
-	row := strings.Join(parts, "   ")
+	row := strings.Join(parts, renderSeparator(m.styles, m.cfg.ColumnSeparator(), m.styles.Background))
 	return m.styles.Info.Width(width).Render(row)
 }
 
-func (m Model) renderContentTitle(title string, width int) string {
+// renderSeparator draws the column separator sep in the muted color over
+// bg, the background of the row it sits in.
+func renderSeparator(styles theme.Styles, sep string, bg lipgloss.Color) string {
+	return lipgloss.NewStyle().Foreground(styles.Muted).Background(bg).Render(sep)
+}
+
+// contentBoxStyle applies the active tab's accent, if any, to the border,
+// and drops the padding and border as dense and hide_border ask.
+func (m Model) contentBoxStyle() lipgloss.Style {
+	box := m.styles.ContentBox
+	if m.cfg.Dense {
+		box = box.Padding(0)
+	}
+	if m.cfg.HideBorder {
+		box = box.Border(lipgloss.Border{}, false)
+	}
+	if accent := m.tabs[m.active].Accent; accent != "" {
+		box = box.BorderForeground(lipgloss.Color(accent))
+	}
+	return box
+}
+
+func (m Model) renderContentTitle(title string, stale error, width int) string {
 	if width <= 0 {
 		return ""
 	}
 	label := fmt.Sprintf(" %s ", title)
+	if stale != nil {
+		label += lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("(stale — last error: %v) ", stale))
+	}
 	return m.styles.Summary.Width(width).Render(label)
 }
 
+// staleErr returns the active tab's latest error when the viewport is
+// still showing output from an earlier successful run, and nil otherwise.
+func (m Model) staleErr() error {
+	err := m.lastErr[m.active]
+	if err == nil {
+		return nil
+	}
+	if _, ok := m.lastGood[m.active]; !ok {
+		return nil
+	}
+	return err
+}
+
 func (m Model) renderFooter(status, spinner string, width int) string {
-	help := "q:quit  tab/shift+tab:next/prev  up/down/pgup/pgdn:scroll  t:theme"
+	help := "q:quit  tab/shift+tab:next/prev  up/down/pgup/pgdn:scroll  j/k:select  enter:copy  t:theme  r:refresh  c:clear  L:lines  n:variant  [/]:smoothing  a:ansi  b:bars  m:history  e:edit config"
 	if status != "" {
 		help = spinner + "  " + status + "  |  " + help
 	} else if spinner != "" {
 		help = spinner + "  " + help
 	}
-	return m.styles.Footer.Width(width).Render(help)
+	// A long status wraps the help further; cut it rather than push the
+	// frame past the window.
+	return m.styles.Footer.Width(width).MaxHeight(footerRows).Render(help)
 }
 
-func sparkline(values []float64, min, max float64) string {
-	if len(values) == 0 {
-		return ""
-	}
-	if max <= min {
-		max = min + 1
+// renderStderrNote dims warnings a successful command wrote to stderr so
+// they read as an aside next to the status.
+func (m Model) renderStderrNote(note string) string {
+	return m.styles.Footer.Padding(0).Faint(true).Render(note)
+}
+
+// exitCode extracts the exit status of a command that ran and exited; it
+// reports false for failures to start and for killed processes.
+func exitCode(err error) (int, bool) {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() < 0 {
+		return 0, false
 	}
-	levels := []rune(" .:-=+*#%@")
-	var b strings.Builder
-	for _, v := range values {
-		if v < min {
-			v = min
-		}
-		if v > max {
-			v = max
-		}
-		n := int(((v - min) / (max - min)) * float64(len(levels)-1))
-		if n < 0 {
-			n = 0
-		}
-		if n >= len(levels) {
-			n = len(levels) - 1
-		}
-		b.WriteRune(levels[n])
+	return exitErr.ExitCode(), true
+}
+
+func formatTook(d time.Duration) string {
+	return fmt.Sprintf("%0.1fs", d.Seconds())
+}
+
+// formatUsage describes a child's resource use for the status line, e.g.
+// "(pid 4242, cpu 0.15s, rss 3.5MiB)".
+func formatUsage(u monitor.ProcUsage) string {
+	return fmt.Sprintf("(pid %d, cpu %0.2fs, rss %s)", u.PID, u.CPU.Seconds(), monitor.FormatBytes(float64(u.RSSBytes)))
+}
+
+func firstLine(s string) string {
+	if idx := strings.IndexByte(s, '\n'); idx != -1 {
+		return strings.TrimSpace(s[:idx])
 	}
-	return b.String()
+	return s
 }
 
 func isQuitKey(msg tea.KeyMsg) bool {
@@ -487,19 +1081,6 @@ func isQuitKey(msg tea.KeyMsg) bool {
 	return false
 }
 
-func maxFloat(values []float64) float64 {
-	if len(values) == 0 {
-		return 0
-	}
-	out := values[0]
-	for _, v := range values[1:] {
-		if v > out {
-			out = v
-		}
-	}
-	return out
-}
-
 func clampMin(value, min int) int {
 	if value < min {
 		return min
@@ -1,11 +1,23 @@
 package ui
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/internal/theme"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 func TestModelNavigation(t *testing.T) {
@@ -79,6 +91,19 @@ func TestThemeToggle(t *testing.T) {
 	if updatedM.themeIndex == initialTheme {
 		t.Error("Theme index should change after pressing 't'")
 	}
+
+	updatedM.width, updatedM.height = 200, 24
+	want := "theme: " + theme.Themes[updatedM.themeIndex].Name
+	if !strings.Contains(updatedM.View(), want) {
+		t.Errorf("Expected %q in the status after pressing 't'", want)
+	}
+
+	updatedM.tabs = []config.Tab{{Title: "Tab 1", Cmd: []string{"echo"}}}
+	updatedM.active = 0
+	updatedM.applyResult(cmdResultMsg{output: "ok"})
+	if strings.Contains(updatedM.View(), want) {
+		t.Errorf("Expected %q to clear on the next command update", want)
+	}
 }
 
 func TestQuit(t *testing.T) {
@@ -93,3 +118,746 @@ func TestQuit(t *testing.T) {
 	// We can't easily check if cmd is actually Quit without internal access,
 	// but getting a command back is a good sign here.
 }
+
+func TestCommandStderrSeparated(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "Tab 1", Cmd: []string{"fake"}}}
+	m.active = 0
+
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		return "data line", "warning: something odd\nmore", nil
+	}
+	msg := runCommandCmd(context.Background(), 0, 0, m.tabs[0], m.run)()
+	newM, _ := m.Update(msg)
+	updatedM, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if updatedM.content != "data line" {
+		t.Errorf("Expected stdout only in content, got %q", updatedM.content)
+	}
+	if updatedM.stderrNote != "stderr: warning: something odd" {
+		t.Errorf("Expected stderr note, got %q", updatedM.stderrNote)
+	}
+
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		return "", "permission denied", errors.New("exit status 1")
+	}
+	msg = runCommandCmd(context.Background(), 0, 0, m.tabs[0], m.run)()
+	newM, _ = updatedM.Update(msg)
+	updatedM, ok = newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if updatedM.statusLine != "error: exit status 1: permission denied" {
+		t.Errorf("Expected stderr as error detail, got %q", updatedM.statusLine)
+	}
+	if updatedM.stderrNote != "" {
+		t.Errorf("Expected no stderr note on failure, got %q", updatedM.stderrNote)
+	}
+}
+
+func TestStaleMarker(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "Tab 1", Cmd: []string{"fake"}}}
+	m.active = 0
+
+	m.applyResult(cmdResultMsg{err: errors.New("exit status 1"), stderr: "boom"})
+	if m.staleErr() != nil {
+		t.Error("Expected no stale marker without earlier good output")
+	}
+	if m.content != "boom" {
+		t.Errorf("Expected error output when nothing better exists, got %q", m.content)
+	}
+
+	m.applyResult(cmdResultMsg{output: "good"})
+	if m.staleErr() != nil {
+		t.Error("Expected no stale marker after a successful run")
+	}
+
+	m.applyResult(cmdResultMsg{err: errors.New("exit status 2"), stderr: "boom"})
+	if err := m.staleErr(); err == nil || err.Error() != "exit status 2" {
+		t.Errorf("Expected stale marker with the latest error, got %v", err)
+	}
+	if m.content != "good" {
+		t.Errorf("Expected last good output to stay visible, got %q", m.content)
+	}
+	m.width = 120
+	if title := m.renderContentTitle("Tab 1", m.staleErr(), m.width); !strings.Contains(title, "stale — last error: exit status 2") {
+		t.Errorf("Expected stale marker in title, got %q", title)
+	}
+
+	m.applyResult(cmdResultMsg{output: "fresh"})
+	if m.staleErr() != nil {
+		t.Error("Expected stale marker to clear after recovery")
+	}
+}
+
+func TestClearMetricHistory(t *testing.T) {
+	m := NewModel()
+	m.metrics = monitor.MetricHistory{
+		Load: []float64{1, 2},
+		CPU:  []float64{50},
+		Mem:  []float64{25},
+		Net:  []float64{100},
+	}
+
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}}
+	newM, _ := m.Update(msg)
+	updatedM, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+
+	h := updatedM.metrics
+	if len(h.Load)+len(h.CPU)+len(h.Mem)+len(h.Net) != 0 {
+		t.Errorf("Expected empty history after 'c', got %+v", h)
+	}
+}
+
+func TestClearDropsInFlightSample(t *testing.T) {
+	m := NewModel()
+	stale := metricsMsg{gen: m.metricsGen, metrics: monitor.MetricsSample{CPU: 90, OkCPU: true}}
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	newM, _ = m.Update(stale)
+	if m, ok = newM.(Model); !ok {
+		t.Fatal("Expected Model type")
+	}
+	if len(m.metrics.CPU) != 0 {
+		t.Errorf("Expected a sample taken before 'c' to be dropped, got CPU %v", m.metrics.CPU)
+	}
+
+	newM, _ = m.Update(metricsMsg{gen: m.metricsGen, metrics: monitor.MetricsSample{CPU: 40, OkCPU: true}})
+	if m, ok = newM.(Model); !ok {
+		t.Fatal("Expected Model type")
+	}
+	if len(m.metrics.CPU) != 1 || m.metrics.CPU[0] != 40 {
+		t.Errorf("Expected a fresh sample after 'c', got CPU %v", m.metrics.CPU)
+	}
+}
+
+func TestCommandOutputFiltered(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "cpu", Cmd: []string{"fake"}, FilterRe: regexp.MustCompile("^cpu")}}
+	m.active = 0
+
+	newM, _ := m.Update(cmdResultMsg{output: "header\ncpu0 10\nmem 20\ncpu1 30\n"})
+	updatedM, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if updatedM.content != "cpu0 10\ncpu1 30" {
+		t.Errorf("Expected filtered content, got %q", updatedM.content)
+	}
+}
+
+func TestContentBoxAccent(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{
+		{Title: "plain", Cmd: []string{"echo"}},
+		{Title: "errors", Cmd: []string{"echo"}, Accent: "#f87171"},
+	}
+
+	m.active = 0
+	if got := m.contentBoxStyle().GetBorderTopForeground(); got != m.styles.Muted {
+		t.Errorf("Expected theme border color, got %v", got)
+	}
+
+	m.active = 1
+	if got := m.contentBoxStyle().GetBorderTopForeground(); got != lipgloss.Color("#f87171") {
+		t.Errorf("Expected accent border color, got %v", got)
+	}
+}
+
+func TestLineNumbersToggle(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "Tab 1", Cmd: []string{"echo"}}}
+	m.active = 0
+	m.setContent("a\nb", 0)
+
+	msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}}
+	newM, _ := m.Update(msg)
+	updatedM, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if !updatedM.lineNums {
+		t.Error("Expected line numbers on after 'L'")
+	}
+	if updatedM.content != "a\nb" {
+		t.Errorf("Expected raw content unchanged, got %q", updatedM.content)
+	}
+}
+
+func TestLayout(t *testing.T) {
+	tests := []struct {
+		layout      string
+		metricsLast bool
+	}{
+		{config.LayoutTop, false},
+		{config.LayoutBottom, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			m := NewModel()
+			m.cfg.Layout = tt.layout
+			m.tabs = []config.Tab{{Title: "Tab 1", Cmd: []string{"echo"}}}
+			m.active = 0
+			m.width, m.height = 80, 24
+			m.system = monitor.SystemInfo{Uptime: "UPTIME: 1 day"}
+			m.setContent("BODYMARK", 0)
+
+			lines := strings.Split(m.View(), "\n")
+			if len(lines) > m.height {
+				t.Errorf("View has %d lines, want at most %d", len(lines), m.height)
+			}
+			body, metrics := -1, -1
+			for i, line := range lines {
+				if strings.Contains(line, "BODYMARK") {
+					body = i
+				}
+				if strings.Contains(line, "Waiting for metrics") {
+					metrics = i
+				}
+			}
+			if body == -1 || metrics == -1 {
+				t.Fatalf("missing rows: body=%d metrics=%d", body, metrics)
+			}
+			if (metrics > body) != tt.metricsLast {
+				t.Errorf("metrics row at %d, body at %d; want metrics below body = %t", metrics, body, tt.metricsLast)
+			}
+		})
+	}
+}
+
+func TestStaticTabSkippedOnTick(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{
+		{Title: "fetch", Cmd: []string{"fetch"}, Static: true, RefreshInterval: m.cfg.GlobalRefreshInterval},
+		{Title: "live", Cmd: []string{"live"}, RefreshInterval: m.cfg.GlobalRefreshInterval},
+	}
+	m.active = 0
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		return "output " + cmd[0], "", nil
+	}
+
+	cmd := m.refreshCmd()
+	if cmd == nil {
+		t.Fatal("Expected a static tab to run once before it has output")
+	}
+	newM, _ := m.Update(cmd())
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if m.content != "output fetch" {
+		t.Fatalf("Expected static tab output, got %q", m.content)
+	}
+	if m.refreshCmd() != nil {
+		t.Error("Expected no command for a static tab on tick")
+	}
+
+	m.cfg.Prefetch = true
+	var ran []string
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		ran = append(ran, cmd[0])
+		return "", "", nil
+	}
+	m.refreshCmd()()
+	if len(ran) != 1 || ran[0] != "live" {
+		t.Errorf("Expected prefetch tick to run only the live tab, ran %v", ran)
+	}
+}
+
+func TestStripColorToggle(t *testing.T) {
+	m := NewModel()
+	m.cfg.Prefetch = true
+	m.tabs = []config.Tab{{Title: "Tab 1", Cmd: []string{"echo"}}}
+	m.active = 0
+	m.cache[0] = cmdResultMsg{tab: 0, output: "\x1b[31mred\x1b[0m"}
+	m.applyResult(m.cache[0])
+	if m.content != "\x1b[31mred\x1b[0m" {
+		t.Fatalf("Expected colors kept by default, got %q", m.content)
+	}
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	updatedM, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if !updatedM.stripColor || updatedM.content != "red" {
+		t.Errorf("Expected colors stripped after toggle, got %q", updatedM.content)
+	}
+}
+
+func TestRefreshDoesNotOverlap(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "slow", Cmd: []string{"slow"}, RefreshInterval: m.cfg.GlobalRefreshInterval}}
+	m.active = 0
+
+	var starts atomic.Int32
+	release := make(chan struct{})
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		starts.Add(1)
+		<-release
+		return "done", "", nil
+	}
+
+	first := m.startRefresh()
+	if first == nil {
+		t.Fatal("Expected the first refresh to start a run")
+	}
+	results := make(chan tea.Msg, 1)
+	go func() { results <- first() }()
+
+	// Ticks while the run is pending must not start another.
+	for i := 0; i < 3; i++ {
+		newM, _ := m.Update(tickMsg(time.Now()))
+		next, ok := newM.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+	}
+	if cmd := m.startRefresh(); cmd != nil {
+		t.Error("Expected no refresh while a run is in flight")
+	}
+
+	close(release)
+	newM, _ := m.Update(<-results)
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if got := starts.Load(); got != 1 {
+		t.Errorf("Expected 1 run start, got %d", got)
+	}
+	if m.startRefresh() == nil {
+		t.Error("Expected refresh to start again once the run finished")
+	}
+}
+
+func TestStaleResultIgnored(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{
+		{Title: "a", Cmd: []string{"a"}},
+		{Title: "b", Cmd: []string{"b"}, Cmds: [][]string{{"b"}, {"b2"}}},
+	}
+	m.active = 0
+	m.width = 200
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		return cmd[0], "", nil
+	}
+	update := func(msg tea.Msg) tea.Cmd {
+		newM, cmd := m.Update(msg)
+		next, ok := newM.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+		return cmd
+	}
+
+	lateA := m.onTabSelected()
+	runB := update(tea.KeyMsg{Type: tea.KeyTab})
+	if runB == nil {
+		t.Fatal("Expected tab b to run")
+	}
+	update(runB())
+	update(lateA())
+	if m.content != "b" {
+		t.Errorf("Expected a's late result to be ignored on tab b, got %q", m.content)
+	}
+
+	// A run started before a variant switch must not land as the new one.
+	lateB := m.refreshCmd()
+	runB2 := update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	if runB2 == nil {
+		t.Fatal("Expected the new variant to run despite the pending one")
+	}
+	update(runB2())
+	update(lateB())
+	if m.content != "b2" {
+		t.Errorf("Expected the pre-switch result to be ignored, got %q", m.content)
+	}
+}
+
+func TestHideChromeGrowsViewport(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "Tab 1", Cmd: []string{"echo"}}}
+	m.active = 0
+	m.width, m.height = 200, 30
+	m.sample = monitor.MetricsSample{CPU: 10, OkCPU: true}
+	m.metrics = monitor.UpdateHistory(m.metrics, m.sample, monitor.GapSkip)
+	m.system = monitor.SystemInfo{Uptime: "UPTIME: 1d 2h"}
+	m.setContent("body", 0)
+	before := m.viewport.Height
+
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'b'}})
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if m.showChrome {
+		t.Fatal("Expected chrome hidden after pressing 'b'")
+	}
+	if m.viewport.Height != before+chromeRows {
+		t.Errorf("viewport height = %d, want %d", m.viewport.Height, before+chromeRows)
+	}
+	view := m.View()
+	if strings.Contains(view, "UPTIME") || strings.Contains(view, "CPU") {
+		t.Error("Expected metrics and system rows hidden")
+	}
+	if lines := strings.Count(view, "\n") + 1; lines > m.height {
+		t.Errorf("View has %d lines, want at most %d", lines, m.height)
+	}
+}
+
+func TestNextVariantWraps(t *testing.T) {
+	tests := []struct{ cur, count, want int }{
+		{0, 3, 1},
+		{1, 3, 2},
+		{2, 3, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := nextVariant(tt.cur, tt.count); got != tt.want {
+			t.Errorf("nextVariant(%d, %d) = %d, want %d", tt.cur, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestCommandVariantSelection(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{
+		Title: "mem",
+		Cmd:   []string{"free", "-m"},
+		Cmds:  [][]string{{"free", "-m"}, {"free", "-h"}},
+	}}
+	m.active = 0
+	m.width = 200
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		return strings.Join(cmd, " "), "", nil
+	}
+
+	press := func() {
+		newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+		next, ok := newM.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+		if cmd == nil {
+			t.Fatal("Expected the new variant to run")
+		}
+		newM, _ = m.Update(cmd())
+		if next, ok = newM.(Model); !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+	}
+
+	press()
+	if m.content != "free -h" {
+		t.Errorf("Expected second variant output, got %q", m.content)
+	}
+	if got := m.tabTitle(); got != "mem [2/2: free -h]" {
+		t.Errorf("Expected variant in title, got %q", got)
+	}
+	press()
+	if m.content != "free -m" {
+		t.Errorf("Expected wrap-around to first variant, got %q", m.content)
+	}
+}
+
+func TestExitStatusInStatusLine(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	exitErr := exec.Command("sh", "-c", "exit 2").Run()
+
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "Tab 1", Cmd: []string{"fake"}}}
+	m.active = 0
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "", "bad flag", exitErr
+	}
+	res := runTab(context.Background(), 0, m.tabs[0], m.run)
+	if res.took < 10*time.Millisecond {
+		t.Errorf("Expected run duration to be measured, got %s", res.took)
+	}
+	res.took = 300 * time.Millisecond
+	m.applyResult(res)
+	if m.statusLine != "exit 2 in 0.3s: bad flag" {
+		t.Errorf("Expected exit status in status line, got %q", m.statusLine)
+	}
+
+	m.applyResult(cmdResultMsg{output: "ok", took: 1200 * time.Millisecond})
+	if !strings.HasPrefix(m.statusLine, "exit 0 in 1.2s, updated ") {
+		t.Errorf("Expected success exit status, got %q", m.statusLine)
+	}
+}
+
+func TestLoadingPlaceholderOff(t *testing.T) {
+	off := false
+	m := NewModel()
+	m.cfg.LoadingPlaceholder = &off
+	m.tabs = []config.Tab{
+		{Title: "Tab 1", Cmd: []string{"one"}},
+		{Title: "Tab 2", Cmd: []string{"two"}},
+	}
+	m.active = 0
+	m.applyResult(cmdResultMsg{output: "first tab output"})
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if cmd == nil {
+		t.Fatal("Expected the new tab to run")
+	}
+	if m.content != "first tab output" {
+		t.Errorf("Expected previous content retained, got %q", m.content)
+	}
+
+	m.cfg.LoadingPlaceholder = nil
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'h'}})
+	m, ok = newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if m.content != "Loading..." {
+		t.Errorf("Expected placeholder by default, got %q", m.content)
+	}
+}
+
+func TestScaleLabel(t *testing.T) {
+	m := NewModel()
+	history := monitor.MetricHistory{
+		Load: []float64{0.5, 3.25, 1.0},
+		Net:  []float64{100, 2048, 512},
+	}
+	if row := ansi.Strip(m.renderMetricsRow(history, 200)); strings.Contains(row, "(max") {
+		t.Errorf("scale label shown while disabled: %q", row)
+	}
+
+	m.cfg.ScaleLabel = true
+	row := ansi.Strip(m.renderMetricsRow(history, 200))
+	for _, want := range []string{"(max 3.25)", "(max 2.0MiB/s)"} {
+		if !strings.Contains(row, want) {
+			t.Errorf("metrics row missing %q: %q", want, row)
+		}
+	}
+}
+
+func TestTabAlign(t *testing.T) {
+	tabs := []config.Tab{{Title: "one"}, {Title: "two"}}
+	const width = 60
+	tests := []struct {
+		align string
+		// lead is where "one" starts: flush left, centered or pushed right.
+		lead func(pos int) bool
+	}{
+		{config.TabAlignLeft, func(pos int) bool { return pos < 5 }},
+		{config.TabAlignCenter, func(pos int) bool { return pos > 15 && pos < 35 }},
+		{config.TabAlignRight, func(pos int) bool { return pos > 40 }},
+	}
+	for _, tt := range tests {
+		m := NewModel()
+		m.cfg.TabAlign = tt.align
+		out := m.renderTabs(tabs, 0, width)
+		if got := lipgloss.Width(out); got != width {
+			t.Errorf("%s: width = %d, want %d", tt.align, got, width)
+		}
+		plain := ansi.Strip(out)
+		if pos := strings.Index(plain, "one"); !tt.lead(pos) {
+			t.Errorf("%s: tabs start at column %d: %q", tt.align, pos, plain)
+		}
+	}
+
+	// Overflow still fits the header when aligned.
+	many := make([]config.Tab, 20)
+	for i := range many {
+		many[i] = config.Tab{Title: fmt.Sprintf("tab%02d", i)}
+	}
+	m := NewModel()
+	m.cfg.TabAlign = config.TabAlignRight
+	if got := lipgloss.Width(m.renderTabs(many, 10, width)); got != width {
+		t.Errorf("overflow width = %d, want %d", got, width)
+	}
+}
+
+func TestPinnedTabsUnderOverflow(t *testing.T) {
+	const width = 60
+	many := make([]config.Tab, 20)
+	for i := range many {
+		many[i] = config.Tab{Title: fmt.Sprintf("tab%02d", i)}
+	}
+	many[0].Pinned = true
+	many[19].Pinned = true
+
+	m := NewModel()
+	for _, active := range []int{0, 10, 19} {
+		out := m.renderTabs(many, active, width)
+		if got := lipgloss.Width(out); got != width {
+			t.Errorf("active %d: width = %d, want %d", active, got, width)
+		}
+		plain := ansi.Strip(out)
+		for _, want := range []string{"tab00", "tab19", fmt.Sprintf("tab%02d", active)} {
+			if !strings.Contains(plain, want) {
+				t.Errorf("active %d: %q missing from %q", active, want, plain)
+			}
+		}
+		if strings.Index(plain, "tab00") > strings.Index(plain, "tab19") {
+			t.Errorf("active %d: pinned tabs out of order: %q", active, plain)
+		}
+	}
+}
+
+func TestDenseContentBox(t *testing.T) {
+	build := func(dense, hideBorder bool) Model {
+		m := NewModel()
+		m.cfg.Dense = dense
+		m.cfg.HideBorder = hideBorder
+		m.tabs = []config.Tab{{Title: "wide"}}
+		m.active = 0
+		m.width, m.height = 80, 24
+		m.setContent(strings.Repeat("x", 200), 0)
+		return m
+	}
+	normal := build(false, false)
+	dense := build(true, false)
+	bare := build(true, true)
+
+	if dense.viewport.Width <= normal.viewport.Width {
+		t.Errorf("dense viewport width = %d, want more than %d", dense.viewport.Width, normal.viewport.Width)
+	}
+	if bare.viewport.Width != 80 {
+		t.Errorf("borderless dense viewport width = %d, want 80", bare.viewport.Width)
+	}
+	if bare.viewport.Height != dense.viewport.Height+2 {
+		t.Errorf("borderless viewport height = %d, want %d", bare.viewport.Height, dense.viewport.Height+2)
+	}
+	for _, m := range []Model{normal, dense, bare} {
+		for _, line := range strings.Split(m.View(), "\n") {
+			if w := lipgloss.Width(line); w > m.width {
+				t.Fatalf("dense=%t hide_border=%t: line is %d wide, want at most %d", m.cfg.Dense, m.cfg.HideBorder, w, m.width)
+			}
+		}
+	}
+}
+
+func TestCustomSeparator(t *testing.T) {
+	history := monitor.MetricHistory{CPU: []float64{10}, Mem: []float64{20}}
+	info := monitor.SystemInfo{Disk: "DISK: 40%", Uptime: "UPTIME: 1d"}
+
+	m := NewModel()
+	if row := ansi.Strip(m.renderSystemRow(info, 80)); !strings.Contains(row, "DISK: 40%   UPTIME: 1d") {
+		t.Errorf("default separator missing: %q", row)
+	}
+
+	m.cfg.Separator = " │ "
+	metrics := ansi.Strip(m.renderMetricsRow(history, 120))
+	if !strings.Contains(metrics, " │ MEM") {
+		t.Errorf("metrics row without custom separator: %q", metrics)
+	}
+	if row := ansi.Strip(m.renderSystemRow(info, 80)); !strings.Contains(row, "DISK: 40% │ UPTIME: 1d") {
+		t.Errorf("system row without custom separator: %q", row)
+	}
+}
+
+func TestMissingSamples(t *testing.T) {
+	tests := []struct {
+		mode   string
+		length int
+		row    string
+	}{
+		{config.MissingSkip, 1, "CPU 40%"},
+		{config.MissingCarry, 3, "CPU 40%"},
+		{config.MissingGap, 3, "··"},
+	}
+	for _, tt := range tests {
+		m := NewModel()
+		m.cfg.MissingSamples = tt.mode
+		for _, s := range []monitor.MetricsSample{{CPU: 40, OkCPU: true}, {}, {}} {
+			updated, _ := m.Update(metricsMsg{metrics: s})
+			next, ok := updated.(Model)
+			if !ok {
+				t.Fatal("Expected Model type")
+			}
+			m = next
+		}
+		if len(m.metrics.CPU) != tt.length {
+			t.Errorf("%s: CPU history = %v, want %d values", tt.mode, m.metrics.CPU, tt.length)
+		}
+		row := ansi.Strip(m.renderMetricsRow(m.metrics, 120))
+		if !strings.Contains(row, "CPU 40%") || !strings.Contains(row, tt.row) {
+			t.Errorf("%s: metrics row = %q, want the last value and %q", tt.mode, row, tt.row)
+		}
+	}
+}
+
+func TestTimeFormat(t *testing.T) {
+	at := time.Date(2024, 5, 1, 15, 4, 5, 0, time.UTC)
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "t", Cmd: []string{"echo"}}}
+	m.active = 0
+
+	m.applyResult(cmdResultMsg{output: "ok", at: at})
+	if !strings.Contains(m.statusLine, "updated 15:04:05 ") {
+		t.Errorf("default status = %q, want the 15:04:05 layout", m.statusLine)
+	}
+
+	m.cfg.TimeFormat = "2006-01-02 3:04PM"
+	m.applyResult(cmdResultMsg{output: "ok", at: at})
+	if !strings.Contains(m.statusLine, "updated 2024-05-01 3:04PM ") {
+		t.Errorf("custom status = %q, want the time_format layout", m.statusLine)
+	}
+}
+
+func TestConfirmQuit(t *testing.T) {
+	m := NewModel()
+	m.cfg.ConfirmQuit = true
+	q := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}
+
+	updated, cmd := m.Update(q)
+	if cmd != nil {
+		if _, ok := cmd().(tea.QuitMsg); ok {
+			t.Fatal("a single q with confirm_quit should not quit")
+		}
+	}
+	m, ok := updated.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if !strings.Contains(m.View(), "Press q again to quit") {
+		t.Error("expected the confirmation prompt in the footer")
+	}
+
+	_, cmd = m.Update(q)
+	if cmd == nil {
+		t.Fatal("a second q should quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("a second q should return tea.Quit")
+	}
+
+	// An expired prompt has to be confirmed again.
+	m.quitArmedUntil = time.Now().Add(-time.Second)
+	if _, cmd = m.Update(q); cmd != nil {
+		t.Error("q after the confirmation window should ask again")
+	}
+
+	_, cmd = NewModel().Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Error("ctrl+c should quit without confirmation")
+	}
+}
@@ -0,0 +1,30 @@
+package ui
+
+import "github.com/sumant1122/perfdeck/internal/config"
+
+// nextEnabled returns the first enabled tab after from in direction dir
+// (1 or -1), wrapping around. When every other tab is disabled it falls
+// back to the adjacent tab so navigation never stalls or loops forever.
+func nextEnabled(tabs []config.Tab, from, dir int) int {
+	n := len(tabs)
+	if n == 0 {
+		return 0
+	}
+	adjacent := ((from+dir)%n + n) % n
+	for i, step := adjacent, 0; step < n; i, step = ((i+dir)%n+n)%n, step+1 {
+		if !tabs[i].Disabled {
+			return i
+		}
+	}
+	return adjacent
+}
+
+// stepTab moves the active tab by dir, 1 for tab/right/l and -1 for
+// shift+tab/left/h, jumping over disabled tabs when skip_disabled is set.
+func (m *Model) stepTab(dir int) {
+	if m.cfg.SkipDisabled {
+		m.active = nextEnabled(m.tabs, m.active, dir)
+		return
+	}
+	m.active = ((m.active+dir)%len(m.tabs) + len(m.tabs)) % len(m.tabs)
+}
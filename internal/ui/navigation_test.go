@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNextEnabled(t *testing.T) {
+	tabs := func(disabled ...bool) []config.Tab {
+		out := make([]config.Tab, len(disabled))
+		for i, d := range disabled {
+			out[i].Disabled = d
+		}
+		return out
+	}
+	tests := []struct {
+		name      string
+		tabs      []config.Tab
+		from, dir int
+		want      int
+	}{
+		{"next enabled", tabs(false, false, false), 0, 1, 1},
+		{"skips disabled", tabs(false, true, true, false), 0, 1, 3},
+		{"wraps forward", tabs(false, false, true), 1, 1, 0},
+		{"wraps backward", tabs(true, false, false), 1, -1, 2},
+		{"only the current tab enabled", tabs(false, true, true), 0, 1, 0},
+		{"all disabled", tabs(true, true, true), 1, 1, 2},
+		{"all disabled backward", tabs(true, true, true), 0, -1, 2},
+		{"empty", nil, 0, 1, 0},
+	}
+	for _, tt := range tests {
+		if got := nextEnabled(tt.tabs, tt.from, tt.dir); got != tt.want {
+			t.Errorf("%s: nextEnabled(from %d, dir %d) = %d, want %d", tt.name, tt.from, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestSkipDisabledNavigation(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{
+		{Title: "a", Cmd: []string{"echo"}},
+		{Title: "b", Disabled: true, DisabledMsg: "off"},
+		{Title: "c", Cmd: []string{"echo"}},
+	}
+	next := tea.KeyMsg{Type: tea.KeyTab}
+
+	updated, _ := m.Update(next)
+	um, ok := updated.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if um.active != 1 {
+		t.Errorf("without skip_disabled, active = %d, want 1", um.active)
+	}
+
+	m.cfg.SkipDisabled = true
+	updated, _ = m.Update(next)
+	if um, ok = updated.(Model); !ok {
+		t.Fatal("Expected Model type")
+	}
+	if um.active != 2 {
+		t.Errorf("with skip_disabled, active = %d, want 2", um.active)
+	}
+	updated, _ = um.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if um, ok = updated.(Model); !ok {
+		t.Fatal("Expected Model type")
+	}
+	if um.active != 0 {
+		t.Errorf("with skip_disabled going back, active = %d, want 0", um.active)
+	}
+}
+
+func TestSkipDisabledReverse(t *testing.T) {
+	keys := map[string]tea.KeyMsg{
+		"shift+tab": {Type: tea.KeyShiftTab},
+		"left":      {Type: tea.KeyLeft},
+		"h":         {Type: tea.KeyRunes, Runes: []rune{'h'}},
+	}
+	for name, key := range keys {
+		m := NewModel()
+		m.cfg.SkipDisabled = true
+		m.tabs = []config.Tab{
+			{Title: "a", Cmd: []string{"echo"}},
+			{Title: "b", Cmd: []string{"echo"}},
+			{Title: "c", Disabled: true, DisabledMsg: "off"},
+			{Title: "d", Disabled: true, DisabledMsg: "off"},
+			{Title: "e", Cmd: []string{"echo"}},
+		}
+		m.active = 4
+
+		var visited []int
+		for i := 0; i < 4; i++ {
+			updated, _ := m.Update(key)
+			next, ok := updated.(Model)
+			if !ok {
+				t.Fatal("Expected Model type")
+			}
+			m = next
+			visited = append(visited, m.active)
+		}
+		// e -> b -> a -> wraps to e -> b, never landing on c or d.
+		want := []int{1, 0, 4, 1}
+		for i := range want {
+			if visited[i] != want[i] {
+				t.Errorf("%s: visited %v, want %v", name, visited, want)
+				break
+			}
+		}
+	}
+}
+
+func TestSkipDisabledAllButOne(t *testing.T) {
+	m := NewModel()
+	m.cfg.SkipDisabled = true
+	m.tabs = []config.Tab{
+		{Title: "a", Disabled: true, DisabledMsg: "off"},
+		{Title: "b", Cmd: []string{"echo"}},
+		{Title: "c", Disabled: true, DisabledMsg: "off"},
+	}
+	m.active = 1
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyTab}, {Type: tea.KeyShiftTab}, {Type: tea.KeyLeft}, {Type: tea.KeyRight}} {
+		updated, _ := m.Update(key)
+		um, ok := updated.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		if um.active != 1 {
+			t.Errorf("%s: active = %d, want to stay on the only enabled tab 1", key, um.active)
+		}
+	}
+}
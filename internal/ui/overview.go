@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/internal/spark"
+	"github.com/sumant1122/perfdeck/metricstrip"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	overviewLabelWidth = 6
+	minGaugeWidth      = 10
+	maxGaugeWidth      = 50
+	// overviewTopProcs is how many of the busiest processes are listed.
+	overviewTopProcs = 5
+)
+
+// overviewMounts is the overview tab's mounts, "/" when none are set.
+func overviewMounts(t config.Tab) []string {
+	if len(t.Mounts) == 0 {
+		return []string{"/"}
+	}
+	return t.Mounts
+}
+
+// renderOverview draws the overview builtin: gauges and history for every
+// metric the model has sampled, a gauge per disk and the busiest
+// processes, with no external command output.
+func renderOverview(m Model) string {
+	s := m.sample
+	if !s.OkCPU && !s.OkMem && !s.OkLoad && !s.OkNet {
+		return "Waiting for metrics..."
+	}
+
+	gaugeWidth := m.width - 2 - overviewLabelWidth - len(" 100%  ") - monitor.HistoryLength
+	if gaugeWidth > maxGaugeWidth {
+		gaugeWidth = maxGaugeWidth
+	}
+	if gaugeWidth < minGaugeWidth {
+		gaugeWidth = minGaugeWidth
+	}
+	label := lipgloss.NewStyle().Width(overviewLabelWidth).Bold(true)
+	muted := lipgloss.NewStyle().Foreground(m.styles.Muted)
+
+	var rows []string
+	row := func(name, value string) {
+		rows = append(rows, label.Render(name)+value)
+	}
+	gauge := func(name string, pct float64, history []float64, detail string) {
+		style := m.levelStyle(pct)
+		value := fmt.Sprintf("%s %s %s", style.Render(gaugeBar(pct, gaugeWidth)), style.Render(fmt.Sprintf("%3.0f%%", pct)), style.Render(spark.Render(history, 0, 100, m.styles.Ramp)))
+		if detail != "" {
+			value += "  " + muted.Render(detail)
+		}
+		row(name, value)
+	}
+
+	if s.OkCPU {
+		gauge("CPU", s.CPU, m.metrics.CPU, "")
+	} else {
+		row("CPU", muted.Render("n/a"))
+	}
+	if s.OkMem {
+		var detail string
+		if s.MemTotalMB > 0 {
+			detail = fmt.Sprintf("%s/%sG", monitor.FormatGB(s.MemUsedMB), monitor.FormatGB(s.MemTotalMB))
+		}
+		gauge("MEM", s.Mem, m.metrics.Mem, detail)
+	} else {
+		row("MEM", muted.Render("n/a"))
+	}
+	if s.SwapTotalMB > 0 {
+		pct := s.SwapUsedMB / s.SwapTotalMB * 100
+		gauge("SWAP", pct, nil, fmt.Sprintf("%s/%sG", monitor.FormatGB(s.SwapUsedMB), monitor.FormatGB(s.SwapTotalMB)))
+	} else {
+		row("SWAP", muted.Render("n/a"))
+	}
+
+	rows = append(rows, "")
+	if s.OkLoad {
+		row("LOAD", fmt.Sprintf("%0.2f  %0.2f  %0.2f  %s", s.Load, s.Load5, s.Load15, muted.Render("(1m 5m 15m)")))
+	} else {
+		row("LOAD", muted.Render("n/a"))
+	}
+	if s.OkNet {
+		max := monitor.Max(m.metrics.Net)
+		if max < 1 {
+			max = 1
+		}
+		row("NET", fmt.Sprintf("%s  %s", m.formatRate(s.NetKB), spark.Render(m.metrics.Net, 0, max, m.styles.Ramp)))
+	} else {
+		row("NET", muted.Render("n/a"))
+	}
+
+	if len(m.overview.Disks) > 0 {
+		rows = append(rows, "")
+		for _, d := range m.overview.Disks {
+			gauge("DISK", d.Pct, nil, fmt.Sprintf("%s %s/%sG", d.Mount, monitor.FormatGB(d.UsedKB/1024), monitor.FormatGB(d.SizeKB/1024)))
+		}
+	}
+	if len(m.overview.Procs) > 0 {
+		header := fmt.Sprintf("%7s %6s %6s  %s", "PID", "CPU%", "MEM%", "COMMAND")
+		if m.overview.ProcCount > 0 {
+			header += fmt.Sprintf("  (of %s processes)", monitor.FormatCount(m.overview.ProcCount))
+		}
+		rows = append(rows, "", label.Render("TOP")+muted.Render(header))
+		for _, p := range m.overview.Procs {
+			row("", fmt.Sprintf("%7d %6.1f %6.1f  %s", p.PID, p.CPU, p.Mem, p.Command))
+		}
+	}
+
+	var info []string
+	disk := m.system.Disk
+	if len(m.overview.Disks) > 0 {
+		disk = ""
+	}
+	for _, part := range []string{disk, m.system.Uptime, m.system.OS} {
+		if part != "" {
+			info = append(info, part)
+		}
+	}
+	if len(info) > 0 {
+		rows = append(rows, "")
+		rows = append(rows, info...)
+	}
+	return strings.Join(rows, "\n")
+}
+
+// refreshOverview re-renders the overview tab when it is showing, so it
+// tracks every new sample rather than only the tab's own refresh.
+func (m *Model) refreshOverview() {
+	if len(m.tabs) == 0 || m.tabs[m.active].Builtin != config.BuiltinOverview {
+		return
+	}
+	m.setContent(renderOverview(*m), 0)
+}
+
+// levelStyle colors a percentage the way the metrics row grades it.
+func (m Model) levelStyle(pct float64) lipgloss.Style {
+	return metricstrip.PercentStyle(pct, m.styles)
+}
+
+// gaugeBar renders pct as a fixed-width bar like [#####-----].
+func gaugeBar(pct float64, width int) string {
+	if pct < 0 {
+		pct = 0
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	filled := int(pct / 100 * float64(width))
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/internal/theme"
+)
+
+func TestRenderOverview(t *testing.T) {
+	sample := monitor.MetricsSample{
+		Load: 0.5, Load5: 0.75, Load15: 1.25,
+		CPU: 42, Mem: 63, NetKB: 512,
+		MemUsedMB: 4096, MemTotalMB: 8192,
+		SwapUsedMB: 256, SwapTotalMB: 2048,
+		OkLoad: true, OkCPU: true, OkMem: true, OkNet: true,
+	}
+	m := Model{
+		tabs:       []config.Tab{{Title: "overview", Builtin: config.BuiltinOverview}},
+		width:      100,
+		height:     40,
+		styles:     theme.BuildStyles(0),
+		formatRate: monitor.FormatRate,
+		sample:     sample,
+		metrics:    monitor.UpdateHistory(monitor.MetricHistory{}, sample, monitor.GapSkip),
+		system:     monitor.SystemInfo{Disk: "DISK: / 100G used 40G (40%)", Uptime: "UPTIME: 3 days"},
+	}
+
+	out := renderOverview(m)
+	for _, want := range []string{"CPU", "42%", "MEM", "4.0/8.0G", "SWAP", "0.50  0.75  1.25", "512KiB/s", "DISK: /"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("overview missing %q:\n%s", want, out)
+		}
+	}
+
+	m.overview = monitor.OverviewSample{
+		Disks: []monitor.DiskUsage{
+			{Mount: "/", SizeKB: 100 << 20, UsedKB: 40 << 20, Pct: 40},
+			{Mount: "/home", SizeKB: 500 << 20, UsedKB: 450 << 20, Pct: 90},
+		},
+		Procs: []monitor.Process{
+			{PID: 812, CPU: 23.5, Mem: 4.1, Command: "postgres"},
+			{PID: 1290, CPU: 12, Mem: 2.6, Command: "nginx -g daemon off;"},
+		},
+		ProcCount: 1234,
+	}
+	out = renderOverview(m)
+	for _, want := range []string{"/ 40.0/100.0G", "/home 450.0/500.0G", " 90%", "TOP", "COMMAND", "of 1.2k processes", "812   23.5    4.1  postgres", "nginx -g daemon off;"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("overview missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "DISK: /") {
+		t.Errorf("overview repeats the system disk summary next to the gauges:\n%s", out)
+	}
+
+	m.sample = monitor.MetricsSample{}
+	if out := renderOverview(m); !strings.Contains(out, "Waiting") {
+		t.Errorf("overview without samples = %q, want a waiting message", out)
+	}
+}
+
+func TestGaugeBar(t *testing.T) {
+	tests := []struct {
+		pct  float64
+		want string
+	}{
+		{0, "[----]"},
+		{50, "[##--]"},
+		{100, "[####]"},
+		{150, "[####]"},
+	}
+	for _, tt := range tests {
+		if got := gaugeBar(tt.pct, 4); got != tt.want {
+			t.Errorf("gaugeBar(%v) = %q, want %q", tt.pct, got, tt.want)
+		}
+	}
+}
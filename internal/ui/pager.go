@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// pagerLookPath finds pager binaries; tests replace it.
+var pagerLookPath = exec.LookPath
+
+// pagerClosedMsg reports that the external pager exited.
+type pagerClosedMsg struct {
+	path string
+	err  error
+}
+
+// pagerView is the built-in full-screen pager used when no external pager
+// is installed. It shows a snapshot of the output, so refreshes underneath
+// do not move it.
+type pagerView struct {
+	open     bool
+	viewport viewport.Model
+	lines    []string
+	// query is the search text; searching is set while it is being typed.
+	query     string
+	searching bool
+	// matches are the line numbers containing query, and match the one
+	// currently shown.
+	matches []int
+	match   int
+}
+
+// pagerCommand picks the external pager: $PAGER when it resolves, then
+// less, then more. It returns nil when none is available.
+func pagerCommand(env string, lookPath func(string) (string, error)) []string {
+	if fields := strings.Fields(env); len(fields) > 0 {
+		if _, err := lookPath(fields[0]); err == nil {
+			return fields
+		}
+	}
+	if _, err := lookPath("less"); err == nil {
+		return []string{"less", "-R"}
+	}
+	if _, err := lookPath("more"); err == nil {
+		return []string{"more"}
+	}
+	return nil
+}
+
+// openPager shows content in an external pager, or in the built-in one
+// when there is no pager or the content cannot be handed over.
+func (m *Model) openPager(content string) tea.Cmd {
+	pager := pagerCommand(os.Getenv("PAGER"), pagerLookPath)
+	if pager == nil {
+		m.showPager(content)
+		return nil
+	}
+	f, err := os.CreateTemp("", "perfdeck-*.txt")
+	if err != nil {
+		m.showPager(content)
+		return nil
+	}
+	_, err = f.WriteString(content)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		m.showPager(content)
+		return nil
+	}
+	c := exec.Command(pager[0], append(pager[1:], f.Name())...)
+	return tea.ExecProcess(c, func(err error) tea.Msg {
+		return pagerClosedMsg{path: f.Name(), err: err}
+	})
+}
+
+// showPager opens the built-in pager on content.
+func (m *Model) showPager(content string) {
+	vp := viewport.New(m.width, m.pagerHeight())
+	vp.SetContent(content)
+	m.pager = pagerView{open: true, viewport: vp, lines: strings.Split(content, "\n")}
+}
+
+// pagerHeight leaves one row for the pager's status line.
+func (m Model) pagerHeight() int {
+	return clampMin(m.height-1, 0)
+}
+
+// updatePager handles keys while the built-in pager is open: / searches,
+// n and N step through matches, q or Esc close it.
+func (m Model) updatePager(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := &m.pager
+	if p.searching {
+		switch msg.Type {
+		case tea.KeyEnter:
+			p.searching = false
+			p.matches = findMatches(p.lines, p.query)
+			p.match = 0
+			p.jumpToMatch()
+		case tea.KeyEsc:
+			p.searching = false
+			p.query = ""
+		case tea.KeyBackspace:
+			if r := []rune(p.query); len(r) > 0 {
+				p.query = string(r[:len(r)-1])
+			}
+		case tea.KeySpace:
+			p.query += " "
+		case tea.KeyRunes:
+			p.query += string(msg.Runes)
+		}
+		return m, nil
+	}
+	switch msg.String() {
+	case keyCtrlC:
+		return m, tea.Quit
+	case "q", "esc", "p":
+		m.pager = pagerView{}
+		return m, nil
+	case "/":
+		p.searching = true
+		p.query = ""
+		return m, nil
+	case "n", "N":
+		if len(p.matches) > 0 {
+			step := 1
+			if msg.String() == "N" {
+				step = len(p.matches) - 1
+			}
+			p.match = (p.match + step) % len(p.matches)
+			p.jumpToMatch()
+		}
+		return m, nil
+	}
+	var cmd tea.Cmd
+	p.viewport, cmd = p.viewport.Update(msg)
+	return m, cmd
+}
+
+// jumpToMatch scrolls the current match to the top of the pager.
+func (p *pagerView) jumpToMatch() {
+	if p.match < len(p.matches) {
+		p.viewport.SetYOffset(p.matches[p.match])
+	}
+}
+
+// findMatches returns the lines containing query, ignoring case and
+// styling.
+func findMatches(lines []string, query string) []int {
+	if query == "" {
+		return nil
+	}
+	query = strings.ToLower(query)
+	var matches []int
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), query) {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// renderPager draws the built-in pager with its status line.
+func (m Model) renderPager() string {
+	p := m.pager
+	var status string
+	switch {
+	case p.searching:
+		status = "/" + p.query
+	case p.query != "" && len(p.matches) == 0:
+		status = fmt.Sprintf("no match for %q  |  /:search  q:close", p.query)
+	case len(p.matches) > 0:
+		status = fmt.Sprintf("match %d/%d  |  n/N:next/prev  /:search  q:close", p.match+1, len(p.matches))
+	default:
+		status = fmt.Sprintf("line %d/%d  |  /:search  q:close", p.viewport.YOffset+1, len(p.lines))
+	}
+	footer := m.styles.Footer.Width(m.width).MaxWidth(m.width).Render(status)
+	return p.viewport.View() + "\n" + footer
+}
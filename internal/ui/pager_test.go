@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestPagerCommand(t *testing.T) {
+	have := func(names ...string) func(string) (string, error) {
+		return func(file string) (string, error) {
+			for _, n := range names {
+				if n == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	tests := []struct {
+		name   string
+		env    string
+		lookup func(string) (string, error)
+		want   []string
+	}{
+		{"env", "most -s", have("most", "less"), []string{"most", "-s"}},
+		{"env missing", "most", have("less"), []string{"less", "-R"}},
+		{"less", "", have("less", "more"), []string{"less", "-R"}},
+		{"more", "", have("more"), []string{"more"}},
+		{"none", "", have(), nil},
+	}
+	for _, tt := range tests {
+		got := pagerCommand(tt.env, tt.lookup)
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") || (got == nil) != (tt.want == nil) {
+			t.Errorf("%s: pagerCommand = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBuiltinPagerFallback(t *testing.T) {
+	orig := pagerLookPath
+	t.Cleanup(func() { pagerLookPath = orig })
+	pagerLookPath = func(string) (string, error) { return "", errors.New("not found") }
+	t.Setenv("PAGER", "")
+
+	var lines []string
+	for i := 0; i < 30; i++ {
+		lines = append(lines, fmt.Sprintf("row %02d", i))
+	}
+	m := NewModel()
+	m.width, m.height = 80, 40
+	m.setContent(strings.Join(lines, "\n"), 0)
+
+	newM, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'p'}})
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if cmd != nil || !m.pager.open {
+		t.Fatal("expected the built-in pager to open")
+	}
+	view := ansi.Strip(m.View())
+	for _, line := range lines {
+		if !strings.Contains(view, line) {
+			t.Errorf("pager view missing %q", line)
+		}
+	}
+
+	// The snapshot survives a refresh underneath.
+	m.setContent("replaced", 0)
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "row 29") {
+		t.Error("pager should keep showing the content it was opened with")
+	}
+
+	newM, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if m, ok = newM.(Model); !ok {
+		t.Fatal("Expected Model type")
+	}
+	if m.pager.open {
+		t.Error("q should close the pager")
+	}
+}
+
+func TestPagerSearch(t *testing.T) {
+	m := NewModel()
+	m.width, m.height = 80, 5
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	lines[20] = "needle here"
+	lines[40] = "another NEEDLE"
+	m.showPager(strings.Join(lines, "\n"))
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'/'}},
+		{Type: tea.KeyRunes, Runes: []rune("needle")},
+		{Type: tea.KeyEnter},
+	}
+	for _, k := range keys {
+		newM, _ := m.Update(k)
+		next, ok := newM.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+	}
+	if got := m.pager.matches; len(got) != 2 || got[0] != 20 || got[1] != 40 {
+		t.Fatalf("matches = %v, want [20 40]", got)
+	}
+	if m.pager.viewport.YOffset != 20 {
+		t.Errorf("YOffset = %d, want 20", m.pager.viewport.YOffset)
+	}
+	newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'n'}})
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "another NEEDLE") || !strings.Contains(view, "match 2/2") {
+		t.Errorf("n should jump to the second match: %q", view)
+	}
+}
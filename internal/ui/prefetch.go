@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prefetchConcurrency caps how many tab commands run at once when
+// prefetching so a long tab list doesn't fork everything simultaneously.
+const prefetchConcurrency = 4
+
+// prefetchKey marks a pending prefetch in Model.inFlight.
+const prefetchKey = -1
+
+type prefetchMsg struct {
+	gen     int
+	results []cmdResultMsg
+}
+
+// prefetchCmd runs every enabled tab and reports all results together.
+// Static tabs already in cache are not run again.
+func prefetchCmd(ctx context.Context, gen int, tabs []config.Tab, cache map[int]cmdResultMsg, run runner) tea.Cmd {
+	// Decide up front: the cache belongs to the model and must not be read
+	// from the command's goroutine.
+	skip := make([]bool, len(tabs))
+	now := time.Now()
+	for i, t := range tabs {
+		res, cached := cache[i]
+		skip[i] = t.Disabled || (t.Static && cached) || (cached && cacheFresh(t, res, now))
+	}
+	return func() tea.Msg {
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			results []cmdResultMsg
+		)
+		sem := make(chan struct{}, prefetchConcurrency)
+		for i, t := range tabs {
+			if skip[i] {
+				continue
+			}
+			wg.Add(1)
+			go func(i int, t config.Tab) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				res := runTab(ctx, i, t, run)
+				res.gen = gen
+				mu.Lock()
+				results = append(results, res)
+				mu.Unlock()
+			}(i, t)
+		}
+		wg.Wait()
+		return prefetchMsg{gen: gen, results: results}
+	}
+}
@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPrefetchServesCachedContent(t *testing.T) {
+	var runs atomic.Int32
+	m := NewModel()
+	m.cfg.Prefetch = true
+	m.tabs = []config.Tab{
+		{Title: "Tab 1", Cmd: []string{"one"}},
+		{Title: "Tab 2", Cmd: []string{"two"}},
+		{Title: "Off", Cmd: []string{"off"}, Disabled: true},
+	}
+	m.active = 0
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		runs.Add(1)
+		return "output " + cmd[0], "", nil
+	}
+
+	msg := prefetchCmd(context.Background(), 0, m.tabs, nil, m.run)()
+	if got := runs.Load(); got != 2 {
+		t.Fatalf("Expected 2 runs for enabled tabs, got %d", got)
+	}
+	newM, _ := m.Update(msg)
+	updatedM, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if updatedM.content != "output one" {
+		t.Errorf("Expected active tab output, got %q", updatedM.content)
+	}
+
+	newM, cmd := updatedM.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'l'}})
+	updatedM, ok = newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if cmd != nil {
+		t.Error("Expected no command when switching to a prefetched tab")
+	}
+	if updatedM.content != "output two" {
+		t.Errorf("Expected cached output on switch, got %q", updatedM.content)
+	}
+	if got := runs.Load(); got != 2 {
+		t.Errorf("Expected no extra runs on switch, got %d", got)
+	}
+}
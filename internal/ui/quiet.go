@@ -0,0 +1,27 @@
+package ui
+
+// countRun tracks a tab's consecutive failed runs, resetting on success.
+func (m *Model) countRun(res cmdResultMsg) {
+	if res.err != nil {
+		m.failures[res.tab]++
+		return
+	}
+	delete(m.failures, res.tab)
+}
+
+// surfaceError reports whether a tab that has failed failures times in a
+// row should show the error, given the quiet_errors threshold.
+func surfaceError(failures, threshold int) bool {
+	return failures >= threshold
+}
+
+// quiet reports whether a failed result for the active tab should be
+// dropped, leaving the last good output and status on screen. A tab with
+// nothing to fall back on shows the error straight away.
+func (m Model) quiet(res cmdResultMsg) bool {
+	if res.err == nil || surfaceError(m.failures[m.active], m.cfg.QuietErrors) {
+		return false
+	}
+	_, ok := m.lastGood[m.active]
+	return ok
+}
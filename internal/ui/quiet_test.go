@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+)
+
+func TestSurfaceError(t *testing.T) {
+	tests := []struct {
+		failures, threshold int
+		want                bool
+	}{
+		{1, 0, true},
+		{1, 1, true},
+		{1, 3, false},
+		{2, 3, false},
+		{3, 3, true},
+		{4, 3, true},
+	}
+	for _, tt := range tests {
+		if got := surfaceError(tt.failures, tt.threshold); got != tt.want {
+			t.Errorf("surfaceError(%d, %d) = %t, want %t", tt.failures, tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestQuietErrors(t *testing.T) {
+	m := NewModel()
+	m.cfg.QuietErrors = 3
+	m.tabs = []config.Tab{{Title: "flaky", Cmd: []string{"fake"}}}
+	m.active = 0
+	fail := false
+	m.run = func(ctx context.Context, cmd []string) (string, string, error) {
+		if fail {
+			return "", "boom", errors.New("flaky")
+		}
+		return "good", "", nil
+	}
+	step := func() {
+		t.Helper()
+		newM, _ := m.Update(runCommandCmd(context.Background(), 0, 0, m.tabs[0], m.run)())
+		next, ok := newM.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+	}
+
+	step()
+	fail = true
+	for i := 1; i < 3; i++ {
+		step()
+		if strings.Contains(m.statusLine, "error") || m.content != "good" || m.staleErr() != nil {
+			t.Fatalf("failure %d surfaced: status %q, content %q", i, m.statusLine, m.content)
+		}
+	}
+	step()
+	if !strings.Contains(m.statusLine, "error") || m.staleErr() == nil {
+		t.Errorf("third failure status = %q, want the error shown", m.statusLine)
+	}
+
+	// A success resets the count.
+	fail = false
+	step()
+	fail = true
+	step()
+	if m.failures[0] != 1 || strings.Contains(m.statusLine, "error") {
+		t.Errorf("after a success, failures = %d and status %q; want 1 and quiet", m.failures[0], m.statusLine)
+	}
+}
@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// quitConfirmWindow is how long a first quit key press stays armed when
+// confirm_quit is on.
+const quitConfirmWindow = 2 * time.Second
+
+// shouldQuit reports whether a quit key press ends the program. With
+// confirm_quit, the first press only arms quitting and asks for a second
+// press within quitConfirmWindow. Ctrl+C always quits.
+func (m *Model) shouldQuit(msg tea.KeyMsg, now time.Time) bool {
+	if !m.cfg.ConfirmQuit || msg.Type == tea.KeyCtrlC {
+		return true
+	}
+	if now.Before(m.quitArmedUntil) {
+		return true
+	}
+	m.quitArmedUntil = now.Add(quitConfirmWindow)
+	m.notice = "Press q again to quit"
+	m.noticeUntil = m.quitArmedUntil
+	return false
+}
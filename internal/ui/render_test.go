@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden frames in testdata")
+
+// renderFrame drives a model with fixed tabs and output through a window
+// resize, as the program would on startup, and returns the plain frame.
+// It calls Update directly: teatest needs bubbletea 1.3 and Go 1.24.
+func renderFrame(t *testing.T, width, height int) string {
+	t.Helper()
+	m := NewModel()
+	m.cfg = config.Config{}
+	m.tabs = []config.Tab{
+		{Title: "Processes", Cmd: []string{"ps"}},
+		{Title: "Memory", Cmd: []string{"free"}},
+		{Title: "Disks", Cmd: []string{"df"}},
+	}
+	m.active = 0
+	// A zero last tick pins the countdown at 0s.
+	m.lastTick = time.Time{}
+
+	msgs := []tea.Msg{
+		tea.WindowSizeMsg{Width: width, Height: height},
+		cmdResultMsg{tab: 0, output: "PID CMD\n  1 init\n 42 perfdeck", at: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, msg := range msgs {
+		updated, _ := m.Update(msg)
+		var ok bool
+		if m, ok = updated.(Model); !ok {
+			t.Fatal("Expected Model type")
+		}
+	}
+	return ansi.Strip(m.View())
+}
+
+func TestViewFrame(t *testing.T) {
+	frame := renderFrame(t, 80, 24)
+	for _, want := range []string{"Processes", "Memory", "Disks", "Waiting for metrics...", "42 perfdeck"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame missing %q:\n%s", want, frame)
+		}
+	}
+	lines := strings.Split(frame, "\n")
+	if len(lines) > 24 {
+		t.Errorf("frame has %d lines, want at most 24", len(lines))
+	}
+	for i, line := range lines {
+		if w := ansi.StringWidth(line); w > 80 {
+			t.Errorf("line %d is %d wide, want at most 80: %q", i, w, line)
+		}
+	}
+}
+
+func TestViewGolden(t *testing.T) {
+	frame := renderFrame(t, 80, 24)
+	path := filepath.Join("testdata", "view_80x24.golden")
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(frame), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden (run with -update to create it): %v", err)
+	}
+	if frame != string(want) {
+		t.Errorf("frame differs from %s (run with -update if intended)\ngot:\n%s\nwant:\n%s", path, frame, want)
+	}
+}
@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+// runner executes a tab command and returns stdout and stderr separately.
+// The model holds one so tests can swap in a fake.
+type runner func(ctx context.Context, cmd []string) (stdout, stderr string, err error)
+
+// defaultOutputLimit caps how much of a command's stdout, and separately
+// its stderr, is kept when the tab sets no max_output_bytes.
+const defaultOutputLimit = 4 << 20
+
+// limitedBuffer keeps the first limit bytes written to it and silently
+// drops the rest, so a runaway command cannot exhaust memory.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write never fails: dropping the excess instead of erroring keeps the
+// child from dying on a broken pipe.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// String returns what was kept, noting the cut if anything was dropped.
+func (b *limitedBuffer) String() string {
+	if !b.truncated {
+		return b.buf.String()
+	}
+	return fmt.Sprintf("%s\n(output truncated at %d bytes)", b.buf.String(), b.limit)
+}
+
+type outputLimitKey struct{}
+
+// withOutputLimit returns ctx telling ExecRunner to keep at most limit
+// bytes of each output stream; limit <= 0 leaves the default.
+func withOutputLimit(ctx context.Context, limit int) context.Context {
+	if limit <= 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, outputLimitKey{}, limit)
+}
+
+// outputLimit is the cap ExecRunner applies for ctx.
+func outputLimit(ctx context.Context) int {
+	if limit, ok := ctx.Value(outputLimitKey{}).(int); ok {
+		return limit
+	}
+	return defaultOutputLimit
+}
+
+// usagePollInterval is how often ExecRunner samples its child's resource
+// use while it runs.
+const usagePollInterval = 100 * time.Millisecond
+
+// childUsage collects the resource use of the child a runner spawned. It
+// travels in the context so fake runners can simply ignore it.
+type childUsage struct {
+	mu    sync.Mutex
+	usage monitor.ProcUsage
+	ok    bool
+}
+
+type childUsageKey struct{}
+
+// withChildUsage returns ctx carrying a slot for ExecRunner to report the
+// child's resource use into.
+func withChildUsage(ctx context.Context) (context.Context, *childUsage) {
+	u := &childUsage{}
+	return context.WithValue(ctx, childUsageKey{}, u), u
+}
+
+// record keeps the latest CPU time and the peak resident size seen.
+func (u *childUsage) record(p monitor.ProcUsage) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.ok && u.usage.RSSBytes > p.RSSBytes {
+		p.RSSBytes = u.usage.RSSBytes
+	}
+	u.usage, u.ok = p, true
+}
+
+func (u *childUsage) get() (monitor.ProcUsage, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.usage, u.ok
+}
+
+// ExecRunner runs cmd as a child process, killing it when ctx is done.
+// Each output stream is capped at the context's output limit.
+func ExecRunner(ctx context.Context, cmd []string) (string, string, error) {
+	c := exec.CommandContext(ctx, cmd[0], cmd[1:]...)
+	limit := outputLimit(ctx)
+	stdout := &limitedBuffer{limit: limit}
+	stderr := &limitedBuffer{limit: limit}
+	c.Stdout = stdout
+	c.Stderr = stderr
+
+	log.Printf("exec: %q", cmd)
+	err := c.Start()
+	if err == nil {
+		stopWatch := watchChild(ctx, c.Process.Pid)
+		err = c.Wait()
+		stopWatch()
+	}
+	if err != nil {
+		log.Printf("exec: %q failed: %v", cmd, err)
+	}
+	return stdout.String(), stderr.String(), err
+}
+
+// watchChild samples pid's resource use into the context's childUsage, if
+// it has one, until the returned stop function is called.
+func watchChild(ctx context.Context, pid int) (stop func()) {
+	u, _ := ctx.Value(childUsageKey{}).(*childUsage)
+	if u == nil {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(usagePollInterval)
+		defer ticker.Stop()
+		for {
+			if p, ok := monitor.ReadProcUsage(pid); ok {
+				u.record(p)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+)
+
+func TestRunTabAbortsOnRootCancel(t *testing.T) {
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	root, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	res := runTab(root, 0, config.Tab{Title: "slow", Cmd: []string{"sleep", "10"}}, ExecRunner)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("command ran for %s after root context was cancelled", elapsed)
+	}
+	if res.err == nil {
+		t.Fatal("expected an error from the killed command")
+	}
+}
+
+func TestRunTabReportsChildUsage(t *testing.T) {
+	if _, err := os.Stat("/proc/self/stat"); err != nil {
+		t.Skip("procfs not available")
+	}
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep not available")
+	}
+	res := runTab(context.Background(), 0, config.Tab{Title: "nap", Cmd: []string{"sleep", "0.2"}}, ExecRunner)
+	if res.err != nil {
+		t.Fatal(res.err)
+	}
+	if !res.okUsage || res.usage.PID <= 0 || res.usage.RSSBytes == 0 {
+		t.Errorf("usage = %+v, %t; want the child's pid and rss", res.usage, res.okUsage)
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{limit: 10}
+	for _, chunk := range []string{"hello ", "world", "!!!"} {
+		if n, err := b.Write([]byte(chunk)); n != len(chunk) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v; want %d, nil", chunk, n, err, len(chunk))
+		}
+	}
+	want := "hello worl\n(output truncated at 10 bytes)"
+	if got := b.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	small := &limitedBuffer{limit: 10}
+	if n, err := small.Write([]byte("fits")); n != 4 || err != nil {
+		t.Fatalf("Write(%q) = %d, %v; want 4, nil", "fits", n, err)
+	}
+	if got := small.String(); got != "fits" {
+		t.Errorf("String() under the cap = %q, want %q", got, "fits")
+	}
+}
+
+func TestExecRunnerOutputLimit(t *testing.T) {
+	if _, err := exec.LookPath("yes"); err != nil {
+		t.Skip("yes not available")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	stdout, _, _ := ExecRunner(withOutputLimit(ctx, 64), []string{"yes"})
+	if !strings.HasSuffix(stdout, "(output truncated at 64 bytes)") || len(stdout) > 64+len("\n(output truncated at 64 bytes)") {
+		t.Errorf("stdout = %q, want 64 bytes and the truncation marker", stdout)
+	}
+}
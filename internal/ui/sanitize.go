@@ -2,6 +2,8 @@ package ui
 
 import (
 	"regexp"
+
+	"github.com/charmbracelet/x/ansi"
 )
 
 // sanitizeOutput removes ANSI cursor movement and clear screen codes
@@ -23,3 +25,9 @@ func sanitizeOutput(input string) string {
 	re := regexp.MustCompile(`\x1b\[[\d;?]*[@-ln-~]`)
 	return re.ReplaceAllString(input, "")
 }
+
+// stripAllANSI removes every escape sequence, including SGR colors, for
+// output that should read as plain text.
+func stripAllANSI(input string) string {
+	return ansi.Strip(input)
+}
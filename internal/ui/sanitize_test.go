@@ -46,3 +46,26 @@ func TestSanitizeOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestStripAllANSI(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain text", "hello world", "hello world"},
+		{"SGR color", "\x1b[31mred\x1b[0m", "red"},
+		{"SGR 256 color and bold", "\x1b[1;38;5;208morange\x1b[m", "orange"},
+		{"cursor move", "\x1b[2;5Hmoved", "moved"},
+		{"clear screen and line", "\x1b[2J\x1b[Kclean", "clean"},
+		{"private mode", "\x1b[?25lhidden\x1b[?25h", "hidden"},
+		{"mixed", "\x1b[H\x1b[32mok\x1b[0m done", "ok done"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripAllANSI(tt.input); got != tt.expected {
+				t.Errorf("stripAllANSI(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+const noSelection = -1
+
+// moveSelection moves the highlighted body line by delta, clamped to the
+// content, and scrolls the viewport so the line stays visible.
+func (m *Model) moveSelection(delta int) {
+	if len(m.bodyLines) == 0 {
+		m.selectedLine = noSelection
+		return
+	}
+	if m.selectedLine == noSelection {
+		m.selectedLine = m.viewport.YOffset
+		if delta > 0 {
+			delta--
+		}
+	}
+	m.selectedLine += delta
+	if m.selectedLine < 0 {
+		m.selectedLine = 0
+	}
+	if m.selectedLine > len(m.bodyLines)-1 {
+		m.selectedLine = len(m.bodyLines) - 1
+	}
+	m.setContent(m.content, m.headerRows)
+
+	if m.selectedLine < m.viewport.YOffset {
+		m.viewport.SetYOffset(m.selectedLine)
+	} else if m.viewport.Height > 0 && m.selectedLine >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(m.selectedLine - m.viewport.Height + 1)
+	}
+}
+
+// copySelectionCmd copies the selected line to the system clipboard using
+// the terminal's OSC 52 support.
+func (m Model) copySelectionCmd() tea.Cmd {
+	if m.selectedLine < 0 || m.selectedLine >= len(m.bodyLines) {
+		return nil
+	}
+	line := ansi.Strip(m.bodyLines[m.selectedLine])
+	n := m.selectedLine + 1
+	return func() tea.Msg {
+		termenv.Copy(line)
+		return copiedMsg{line: n}
+	}
+}
+
+type copiedMsg struct {
+	line int
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSelectionBounds(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "Tab 1", Cmd: []string{"echo"}}}
+	m.active = 0
+	m.width, m.height = 80, baseFixedRows+2
+	m.setContent("l1\nl2\nl3\nl4", 0)
+
+	press := func(r rune) {
+		t.Helper()
+		newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		var ok bool
+		if m, ok = newM.(Model); !ok {
+			t.Fatal("Expected Model type")
+		}
+	}
+
+	if m.selectedLine != noSelection {
+		t.Fatalf("Expected no selection initially, got %d", m.selectedLine)
+	}
+
+	press('j')
+	if m.selectedLine != 0 {
+		t.Errorf("Expected first 'j' to select line 0, got %d", m.selectedLine)
+	}
+
+	press('k')
+	if m.selectedLine != 0 {
+		t.Errorf("Expected selection clamped at 0, got %d", m.selectedLine)
+	}
+
+	for i := 0; i < 10; i++ {
+		press('j')
+	}
+	if m.selectedLine != 3 {
+		t.Errorf("Expected selection clamped at last line 3, got %d", m.selectedLine)
+	}
+	if m.viewport.YOffset != 2 {
+		t.Errorf("Expected viewport scrolled to keep selection visible, got offset %d", m.viewport.YOffset)
+	}
+
+	press('k')
+	press('k')
+	press('k')
+	if m.selectedLine != 0 || m.viewport.YOffset != 0 {
+		t.Errorf("Expected selection 0 at offset 0, got %d at %d", m.selectedLine, m.viewport.YOffset)
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+// alphaStep is how much one key press changes the smoothing alpha.
+const alphaStep = 0.1
+
+// stepAlpha moves alpha by delta, clamped to [config.MinSmoothing,1] and
+// rounded to one decimal so repeated steps don't accumulate float error.
+func stepAlpha(alpha, delta float64) float64 {
+	return clampAlpha(math.Round((alpha+delta)*10) / 10)
+}
+
+func clampAlpha(alpha float64) float64 {
+	return math.Min(math.Max(alpha, config.MinSmoothing), 1)
+}
+
+// smoothingText shows the smoothing alpha in the status line, e.g.
+// "alpha 0.7", or nothing while samples are unsmoothed.
+func (m Model) smoothingText() string {
+	if m.alpha >= 1 {
+		return ""
+	}
+	return fmt.Sprintf("alpha %.1f", m.alpha)
+}
+
+// smoothSample blends each metric in s with the latest value in history as
+// an exponentially weighted moving average: alpha*new + (1-alpha)*prev.
+func smoothSample(history monitor.MetricHistory, s monitor.MetricsSample, alpha float64) monitor.MetricsSample {
+	if alpha >= 1 {
+		return s
+	}
+	blend := func(v float64, prev []float64) float64 {
+		last, ok := monitor.Latest(prev)
+		if !ok {
+			return v
+		}
+		return alpha*v + (1-alpha)*last
+	}
+	if s.OkCPU {
+		s.CPU = blend(s.CPU, history.CPU)
+	}
+	if s.OkMem {
+		s.Mem = blend(s.Mem, history.Mem)
+	}
+	if s.OkLoad {
+		s.Load = blend(s.Load, history.Load)
+	}
+	if s.OkNet {
+		s.NetKB = blend(s.NetKB, history.Net)
+	}
+	return s
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStepAlpha(t *testing.T) {
+	tests := []struct {
+		alpha, delta, want float64
+	}{
+		{0.5, alphaStep, 0.6},
+		{0.5, -alphaStep, 0.4},
+		{1, alphaStep, 1},
+		{0.1, -alphaStep, 0.1},
+		{0.2, -alphaStep, 0.1},
+		{0.7, alphaStep, 0.8},
+		{1.7, 0, 1},
+		{-0.3, 0, 0.1},
+	}
+	for _, tt := range tests {
+		if got := stepAlpha(tt.alpha, tt.delta); got != tt.want {
+			t.Errorf("stepAlpha(%v, %v) = %v, want %v", tt.alpha, tt.delta, got, tt.want)
+		}
+	}
+}
+
+func TestSmoothSample(t *testing.T) {
+	history := monitor.MetricHistory{CPU: []float64{20}}
+	s := monitor.MetricsSample{CPU: 80, OkCPU: true, Mem: 50, OkMem: true}
+
+	got := smoothSample(history, s, 0.5)
+	if got.CPU != 50 {
+		t.Errorf("smoothed CPU = %v, want 50", got.CPU)
+	}
+	if got.Mem != 50 {
+		t.Errorf("Mem without history = %v, want the raw 50", got.Mem)
+	}
+	if raw := smoothSample(history, s, 1); raw.CPU != 80 {
+		t.Errorf("alpha 1 should leave samples raw, got %v", raw.CPU)
+	}
+}
+
+func TestSmoothingKeys(t *testing.T) {
+	m := NewModel()
+	for i := 0; i < 3; i++ {
+		newM, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'['}})
+		next, ok := newM.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+	}
+	if m.alpha != 0.7 {
+		t.Errorf("alpha = %v, want 0.7 after three decreases", m.alpha)
+	}
+	if m.notice != "smoothing: alpha 0.7" {
+		t.Errorf("Expected alpha in status, got %q", m.notice)
+	}
+	if got := m.smoothingText(); got != "alpha 0.7" {
+		t.Errorf("smoothingText = %q, want the alpha kept in the status line", got)
+	}
+	m.alpha = 1
+	if got := m.smoothingText(); got != "" {
+		t.Errorf("smoothingText = %q, want none without smoothing", got)
+	}
+}
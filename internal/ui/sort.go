@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// sortByColumn orders body's lines by their col-th whitespace-separated
+// field, counting from 1: numerically when both fields are numbers (a
+// trailing % is allowed) and lexically otherwise. Lines without that
+// column go last. The sort is stable, and col 0 leaves body as is.
+func sortByColumn(body string, col int, desc bool) string {
+	if col < 1 {
+		return body
+	}
+	lines := strings.Split(body, "\n")
+	field := func(line string) (string, bool) {
+		fields := strings.Fields(ansi.Strip(line))
+		if len(fields) < col {
+			return "", false
+		}
+		return fields[col-1], true
+	}
+	slices.SortStableFunc(lines, func(a, b string) int {
+		fa, okA := field(a)
+		fb, okB := field(b)
+		switch {
+		case !okA || !okB:
+			// Missing columns sort last whatever the direction.
+			return cmp.Compare(boolRank(!okA), boolRank(!okB))
+		case desc:
+			return compareFields(fb, fa)
+		default:
+			return compareFields(fa, fb)
+		}
+	})
+	return strings.Join(lines, "\n")
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// compareFields compares two column values as numbers when both parse and
+// as strings otherwise.
+func compareFields(a, b string) int {
+	na, errA := strconv.ParseFloat(strings.TrimSuffix(a, "%"), 64)
+	nb, errB := strconv.ParseFloat(strings.TrimSuffix(b, "%"), 64)
+	if errA == nil && errB == nil {
+		return cmp.Compare(na, nb)
+	}
+	return strings.Compare(a, b)
+}
+
+// columnCount is the most whitespace-separated fields on any line.
+func columnCount(body string) int {
+	n := 0
+	for _, line := range strings.Split(body, "\n") {
+		n = max(n, len(strings.Fields(ansi.Strip(line))))
+	}
+	return n
+}
+
+// stepSortColumn moves the sort to the next column, turning it off after
+// the last one.
+func (m *Model) stepSortColumn() {
+	_, body := splitHeader(m.content, m.headerRows)
+	m.sortColumn++
+	if m.sortColumn > columnCount(body) {
+		m.sortColumn = 0
+	}
+	m.setContent(m.content, m.headerRows)
+	m.setNotice(m.sortNotice())
+}
+
+// toggleSortOrder flips between ascending and descending, sorting by the
+// first column if nothing was sorted yet.
+func (m *Model) toggleSortOrder() {
+	m.sortDesc = !m.sortDesc
+	if m.sortColumn == 0 {
+		m.sortColumn = 1
+	}
+	m.setContent(m.content, m.headerRows)
+	m.setNotice(m.sortNotice())
+}
+
+func (m Model) sortNotice() string {
+	if m.sortColumn == 0 {
+		return "sort off"
+	}
+	order := "ascending"
+	if m.sortDesc {
+		order = "descending"
+	}
+	return fmt.Sprintf("sorted by column %d, %s", m.sortColumn, order)
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSortByColumn(t *testing.T) {
+	body := "nginx 120 4.5%\nbash 9 0.1%\npostgres 1024 12%\nshort"
+	tests := []struct {
+		name string
+		col  int
+		desc bool
+		want string
+	}{
+		{"off", 0, false, body},
+		{"numeric", 2, false, "bash 9 0.1%\nnginx 120 4.5%\npostgres 1024 12%\nshort"},
+		{"numeric desc", 2, true, "postgres 1024 12%\nnginx 120 4.5%\nbash 9 0.1%\nshort"},
+		{"percent", 3, true, "postgres 1024 12%\nnginx 120 4.5%\nbash 9 0.1%\nshort"},
+		{"string", 1, false, "bash 9 0.1%\nnginx 120 4.5%\npostgres 1024 12%\nshort"},
+		{"string desc", 1, true, "short\npostgres 1024 12%\nnginx 120 4.5%\nbash 9 0.1%"},
+		{"past the last column", 9, false, body},
+	}
+	for _, tt := range tests {
+		if got := sortByColumn(body, tt.col, tt.desc); got != tt.want {
+			t.Errorf("%s: sortByColumn(col %d, desc %t) =\n%s\nwant\n%s", tt.name, tt.col, tt.desc, got, tt.want)
+		}
+	}
+
+	// Numbers compare by value, not as text: "9" < "10".
+	if got := sortByColumn("a 10\nb 9", 2, false); got != "b 9\na 10" {
+		t.Errorf("numeric sort = %q, want 9 before 10", got)
+	}
+}
+
+func TestSortKeepsHeader(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "ps", Cmd: []string{"echo"}, HeaderLines: 1}}
+	m.active = 0
+	m.width, m.height = 80, 30
+	m.setContent("PID CMD\n30 c\n10 a\n20 b", 1)
+
+	press := func(key rune) {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{key}})
+		next, ok := updated.(Model)
+		if !ok {
+			t.Fatal("Expected Model type")
+		}
+		m = next
+	}
+	press('s')
+	if m.header != "PID CMD" || strings.Join(m.bodyLines, "\n") != "10 a\n20 b\n30 c" {
+		t.Errorf("sorted by column 1: header %q, body %q", m.header, m.bodyLines)
+	}
+	press('S')
+	if strings.Join(m.bodyLines, "\n") != "30 c\n20 b\n10 a" {
+		t.Errorf("descending: body %q", m.bodyLines)
+	}
+	press('s')
+	press('s')
+	if m.sortColumn != 0 || strings.Join(m.bodyLines, "\n") != "30 c\n10 a\n20 b" {
+		t.Errorf("past the last column: sortColumn %d, body %q; want the original order", m.sortColumn, m.bodyLines)
+	}
+}
@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/metricstrip"
+)
+
+// metricsStrip collects the model's settings and samples for the metrics
+// row.
+func (m Model) metricsStrip() metricstrip.Strip {
+	s := metricstrip.Strip{
+		Styles:     m.styles,
+		FormatRate: m.formatRate,
+		Sample:     m.sample,
+		MemDetail:  m.cfg.MemDetail,
+		ScaleLabel: m.cfg.ScaleLabel,
+		PeakHold:   m.cfg.PeakHold,
+		Priority:   m.cfg.MetricsPriority,
+		Separator:  m.cfg.ColumnSeparator(),
+	}
+	if m.cfg.HistoryWindow {
+		s.Window = historyWindow(m.refreshInterval())
+	}
+	for i, d := range m.derived {
+		if i < len(m.derivedHistory) {
+			s.Extra = append(s.Extra, metricstrip.Series{Name: d.name, Values: m.derivedHistory[i]})
+		}
+	}
+	return s
+}
+
+// renderMetricsRow renders the top row of sparklines and current values.
+func (m Model) renderMetricsRow(history monitor.MetricHistory, width int) string {
+	return m.metricsStrip().Render(history, width)
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/metricstrip"
+)
+
+func TestMetricsRowMatchesStrip(t *testing.T) {
+	history := monitor.MetricHistory{
+		CPU:  []float64{10, 40, 90},
+		Mem:  []float64{55, 60},
+		Load: []float64{0.5, 1.5},
+		Net:  []float64{100, 2048},
+	}
+	m := NewModel()
+	m.cfg = config.Config{}
+	m.formatRate = monitor.FormatRate
+
+	for _, width := range []int{200, 60} {
+		want := m.renderMetricsRow(history, width)
+		if got := metricstrip.Render(history, width, m.styles); got != want {
+			t.Errorf("width %d: metricstrip.Render =\n%q\nwant the model's row\n%q", width, got, want)
+		}
+	}
+}
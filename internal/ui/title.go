@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+// missingMetric stands in for a placeholder whose metric has no samples.
+const missingMetric = "--"
+
+// expandTitle substitutes the latest metrics into a tab title template:
+// {cpu} and {mem} as whole percentages, {load} with two decimals and {net}
+// as a rate rendered with formatRate. Unknown placeholders are left as
+// written.
+func expandTitle(template string, history monitor.MetricHistory, formatRate func(float64) string) string {
+	if !strings.Contains(template, "{") {
+		return template
+	}
+	latest := func(values []float64, format func(float64) string) string {
+		v, ok := monitor.Latest(values)
+		if !ok {
+			return missingMetric
+		}
+		return format(v)
+	}
+	percent := func(v float64) string { return fmt.Sprintf("%0.0f", v) }
+	return strings.NewReplacer(
+		"{cpu}", latest(history.CPU, percent),
+		"{mem}", latest(history.Mem, percent),
+		"{load}", latest(history.Load, func(v float64) string { return fmt.Sprintf("%0.2f", v) }),
+		"{net}", latest(history.Net, formatRate),
+	).Replace(template)
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestExpandTitle(t *testing.T) {
+	full := monitor.MetricHistory{
+		CPU:  []float64{10, 42.4},
+		Mem:  []float64{67.6},
+		Load: []float64{1.5},
+		Net:  []float64{2048},
+	}
+	tests := []struct {
+		name     string
+		template string
+		history  monitor.MetricHistory
+		// formatRate renders {net}; nil means monitor.FormatRate.
+		formatRate func(float64) string
+		want       string
+	}{
+		{"plain", "Processes", full, nil, "Processes"},
+		{"cpu", "CPU {cpu}%", full, nil, "CPU 42%"},
+		{"all", "{cpu} {mem} {load} {net}", full, nil, "42 68 1.50 2.0MiB/s"},
+		{"bits", "NET {net}", full, monitor.FormatRateBits, "NET 16.8Mbps"},
+		{"repeated", "{cpu}/{cpu}", full, nil, "42/42"},
+		{"unknown", "Disk {disk} {cpu}", full, nil, "Disk {disk} 42"},
+		{"missing", "CPU {cpu}% NET {net}", monitor.MetricHistory{}, nil, "CPU --% NET --"},
+		{"partly missing", "{mem} {load}", monitor.MetricHistory{Mem: []float64{5}}, nil, "5 --"},
+	}
+	for _, tt := range tests {
+		formatRate := tt.formatRate
+		if formatRate == nil {
+			formatRate = monitor.FormatRate
+		}
+		if got := expandTitle(tt.template, tt.history, formatRate); got != tt.want {
+			t.Errorf("%s: expandTitle(%q) = %q, want %q", tt.name, tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestTitleTemplateRendered(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "CPU {cpu}%"}, {Title: "Mem {mem}%"}}
+	m.active = 0
+	m.metrics = monitor.MetricHistory{CPU: []float64{37}, Mem: []float64{81}}
+
+	bar := ansi.Strip(m.renderTabs(m.tabs, m.active, 80))
+	for _, want := range []string{"CPU 37%", "Mem 81%"} {
+		if !strings.Contains(bar, want) {
+			t.Errorf("tab bar missing %q: %q", want, bar)
+		}
+	}
+	if got := m.tabTitle(); got != "CPU 37%" {
+		t.Errorf("tabTitle = %q, want %q", got, "CPU 37%")
+	}
+}
@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultTransformLines = 10
+
+// applyTransforms runs content through each step in order. Steps are small
+// text filters modeled on their shell namesakes:
+//
+//	grep [-v] PATTERN   keep (or with -v drop) lines matching PATTERN
+//	head [N]            keep the first N lines (default 10)
+//	tail [N]            keep the last N lines (default 10)
+//	sort [-n] [-r]      sort lines, numerically by first field with -n
+func applyTransforms(content string, steps []string) (string, error) {
+	lines := strings.Split(content, "\n")
+	for _, step := range steps {
+		fields := strings.Fields(step)
+		if len(fields) == 0 {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "grep":
+			lines, err = grepLines(lines, fields[1:])
+		case "head":
+			var n int
+			if n, err = transformCount(fields[1:]); err == nil && n < len(lines) {
+				lines = lines[:n]
+			}
+		case "tail":
+			var n int
+			if n, err = transformCount(fields[1:]); err == nil && n < len(lines) {
+				lines = lines[len(lines)-n:]
+			}
+		case "sort":
+			err = sortLines(lines, fields[1:])
+		default:
+			err = fmt.Errorf("unknown transform %q", fields[0])
+		}
+		if err != nil {
+			return content, fmt.Errorf("transform %q: %w", step, err)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func grepLines(lines, args []string) ([]string, error) {
+	invert := false
+	if len(args) > 0 && args[0] == "-v" {
+		invert = true
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing pattern")
+	}
+	re, err := regexp.Compile(strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if re.MatchString(line) != invert {
+			kept = append(kept, line)
+		}
+	}
+	return kept, nil
+}
+
+func transformCount(args []string) (int, error) {
+	if len(args) == 0 {
+		return defaultTransformLines, nil
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid line count %q", args[0])
+	}
+	return n, nil
+}
+
+func sortLines(lines, args []string) error {
+	var numeric, reverse bool
+	for _, arg := range args {
+		switch arg {
+		case "-n":
+			numeric = true
+		case "-r":
+			reverse = true
+		case "-nr", "-rn":
+			numeric, reverse = true, true
+		default:
+			return fmt.Errorf("unknown flag %q", arg)
+		}
+	}
+	less := func(a, b string) bool { return a < b }
+	if numeric {
+		less = func(a, b string) bool { return leadingNumber(a) < leadingNumber(b) }
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		if reverse {
+			return less(lines[j], lines[i])
+		}
+		return less(lines[i], lines[j])
+	})
+	return nil
+}
+
+// leadingNumber parses the first field of line, treating non-numbers as 0
+// like sort -n does.
+func leadingNumber(line string) float64 {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return 0
+	}
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
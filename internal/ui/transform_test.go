@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestApplyTransforms(t *testing.T) {
+	input := "nginx 3\nsshd 1\nnginx 10\ncron 2\nnginx 1"
+
+	tests := []struct {
+		name     string
+		input    string
+		steps    []string
+		expected string
+	}{
+		{"grep", input, []string{"grep nginx"}, "nginx 3\nnginx 10\nnginx 1"},
+		{"grep invert", input, []string{"grep -v nginx"}, "sshd 1\ncron 2"},
+		{"head", input, []string{"head 2"}, "nginx 3\nsshd 1"},
+		{"head beyond length", input, []string{"head 50"}, input},
+		{"tail", input, []string{"tail 2"}, "cron 2\nnginx 1"},
+		{"sort", input, []string{"sort"}, "cron 2\nnginx 1\nnginx 10\nnginx 3\nsshd 1"},
+		{"sort reverse", input, []string{"sort -r"}, "sshd 1\nnginx 3\nnginx 10\nnginx 1\ncron 2"},
+		{"sort numeric", "10 c\n2 a\n1 b", []string{"sort -n"}, "1 b\n2 a\n10 c"},
+		{"chain grep then head", input, []string{"grep nginx", "head 1"}, "nginx 3"},
+		{"chain head then grep", input, []string{"head 1", "grep sshd"}, ""},
+		{"empty step ignored", input, []string{"", "tail 1"}, "nginx 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyTransforms(tt.input, tt.steps)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("applyTransforms(%v) = %q, want %q", tt.steps, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyTransformsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []string
+	}{
+		{"unknown", []string{"uniq"}},
+		{"grep without pattern", []string{"grep"}},
+		{"bad regex", []string{"grep ([a"}},
+		{"bad count", []string{"head ten"}},
+		{"bad sort flag", []string{"sort -k2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := applyTransforms("a\nb", tt.steps)
+			if err == nil {
+				t.Fatalf("expected error for %v", tt.steps)
+			}
+			if got != "a\nb" {
+				t.Errorf("expected original content on error, got %q", got)
+			}
+		})
+	}
+}
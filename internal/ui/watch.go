@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// configPollInterval is how often configWatchCmd stats the config file.
+var configPollInterval = 2 * time.Second
+
+// configReloadedMsg carries a config that was reloaded after its file
+// changed on disk.
+type configReloadedMsg struct {
+	cfg  config.Config
+	tabs []config.Tab
+}
+
+// configWatchCmd polls path until it changes, then reloads the config.
+// Comparing the file identity as well as size and mtime catches editors
+// that save by writing a temp file and renaming it over the original.
+// While the file is missing, as it briefly is mid-save for some editors,
+// nothing is reloaded, so the tabs don't fall back to the defaults. The
+// watch ends with ctx; Update re-arms it after each reload. The config is
+// reloaded with opts.
+func configWatchCmd(ctx context.Context, path string, opts config.LoadOptions) tea.Cmd {
+	prev, _ := os.Stat(path)
+	return func() tea.Msg {
+		ticker := time.NewTicker(configPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticker.C:
+			}
+			cur, err := os.Stat(path)
+			if err != nil || !fileChanged(prev, cur) {
+				continue
+			}
+			cfg, tabs := config.Load(opts)
+			return configReloadedMsg{cfg: cfg, tabs: tabs}
+		}
+	}
+}
+
+func fileChanged(prev, cur os.FileInfo) bool {
+	switch {
+	case prev == nil && cur == nil:
+		return false
+	case prev == nil || cur == nil:
+		return true
+	}
+	return !os.SameFile(prev, cur) || !prev.ModTime().Equal(cur.ModTime()) || prev.Size() != cur.Size()
+}
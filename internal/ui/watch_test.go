@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestConfigWatchCmdDetectsRename(t *testing.T) {
+	old := configPollInterval
+	configPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { configPollInterval = old })
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "perfdeck.toml")
+	if err := os.WriteFile(path, []byte("[[tab]]\ntitle = \"one\"\ncmd = [\"echo\", \"1\"]\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	t.Setenv("PERFDECK_CONFIG", path)
+
+	cmd := configWatchCmd(context.Background(), path, config.LoadOptions{})
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+
+	// Save the way many editors do: write a sibling file and rename it over
+	// the original.
+	tmp := filepath.Join(dir, "perfdeck.toml.tmp")
+	if err := os.WriteFile(tmp, []byte("[[tab]]\ntitle = \"two\"\ncmd = [\"echo\", \"2\"]\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+
+	select {
+	case got := <-done:
+		msg, ok := got.(configReloadedMsg)
+		if !ok {
+			t.Fatalf("watch returned %T, want configReloadedMsg", got)
+		}
+		if len(msg.tabs) != 1 || msg.tabs[0].Title != "two" {
+			t.Errorf("reloaded tabs = %+v, want the renamed file's tab", msg.tabs)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("config change was not detected")
+	}
+}
+
+func TestConfigWatchCmdIgnoresMissingFile(t *testing.T) {
+	old := configPollInterval
+	configPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { configPollInterval = old })
+
+	path := filepath.Join(t.TempDir(), "perfdeck.toml")
+	if err := os.WriteFile(path, []byte("[[tab]]\ntitle = \"one\"\ncmd = [\"echo\", \"1\"]\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := configWatchCmd(ctx, path, config.LoadOptions{})
+	done := make(chan tea.Msg, 1)
+	go func() { done <- cmd() }()
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	select {
+	case msg := <-done:
+		t.Fatalf("watch returned %T while the file was missing, want no reload", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Cancelling the context ends the watch without a reload.
+	cancel()
+	select {
+	case msg := <-done:
+		if msg != nil {
+			t.Errorf("watch returned %T after cancel, want nil", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("watch did not stop when its context was cancelled")
+	}
+}
+
+func TestEditorExitReloadsOnce(t *testing.T) {
+	old := configPollInterval
+	configPollInterval = 10 * time.Millisecond
+	t.Cleanup(func() { configPollInterval = old })
+
+	path := filepath.Join(t.TempDir(), "perfdeck.toml")
+	t.Setenv("PERFDECK_CONFIG", path)
+	if err := os.WriteFile(path, []byte("[[tab]]\ntitle = \"one\"\ncmd = [\"echo\", \"1\"]\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	m := NewModel()
+	watch := configWatchCmd(context.Background(), path, m.loadOpts)
+	gen := m.runGen
+
+	// The editor saves and exits.
+	if err := os.WriteFile(path, []byte("[[tab]]\ntitle = \"second\"\ncmd = [\"echo\", \"2\"]\n"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	newM, cmd := m.Update(editorClosedMsg{})
+	m, ok := newM.(Model)
+	if !ok {
+		t.Fatal("Expected Model type")
+	}
+	if cmd != nil || m.runGen != gen {
+		t.Fatal("editor exit should leave the reload to the config watch")
+	}
+
+	newM, _ = m.Update(watch())
+	if m, ok = newM.(Model); !ok {
+		t.Fatal("Expected Model type")
+	}
+	if m.runGen != gen+1 {
+		t.Errorf("config reloaded %d times, want once", m.runGen-gen)
+	}
+	if len(m.tabs) != 1 || m.tabs[0].Title != "second" {
+		t.Errorf("tabs = %+v, want the saved config", m.tabs)
+	}
+}
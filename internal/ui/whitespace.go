@@ -0,0 +1,24 @@
+package ui
+
+import "strings"
+
+// normalizeWhitespace trims trailing whitespace from every line and
+// collapses runs of blank lines into a single separating blank line.
+func normalizeWhitespace(s string) string {
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestNormalizeWhitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "already clean",
+			input:    "a\n\nb",
+			expected: "a\n\nb",
+		},
+		{
+			name:     "trailing spaces",
+			input:    "IFACE   rxpck/s   \t\neth0    1.00  ",
+			expected: "IFACE   rxpck/s\neth0    1.00",
+		},
+		{
+			name:     "blank run collapsed",
+			input:    "a\n\n\n\nb",
+			expected: "a\n\nb",
+		},
+		{
+			name:     "whitespace-only lines count as blank",
+			input:    "a\n   \n\t\n\nb",
+			expected: "a\n\nb",
+		},
+		{
+			name:     "carriage returns",
+			input:    "a\r\n\r\n\r\nb\r",
+			expected: "a\n\nb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeWhitespace(tt.input)
+			if got != tt.expected {
+				t.Errorf("normalizeWhitespace(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+)
+
+// historyWindow is the wall-clock time a full sparkline spans: metrics are
+// sampled once per tick, so HistoryLength samples of interval each.
+func historyWindow(interval time.Duration) time.Duration {
+	return monitor.HistoryLength * interval
+}
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestHistoryWindow(t *testing.T) {
+	if got, want := historyWindow(5*time.Second), 150*time.Second; got != want {
+		t.Errorf("historyWindow(5s) = %s, want %s", got, want)
+	}
+}
+
+func TestHistoryWindowLabel(t *testing.T) {
+	m := NewModel()
+	m.tabs = []config.Tab{{Title: "t", Cmd: []string{"echo"}}}
+	m.tabs[0].RefreshInterval.Duration = 5 * time.Second
+	history := monitor.MetricHistory{CPU: []float64{10}, Mem: []float64{20}}
+
+	if row := ansi.Strip(m.renderMetricsRow(history, 120)); strings.Contains(row, "last ") {
+		t.Errorf("window label shown without history_window: %q", row)
+	}
+	m.cfg.HistoryWindow = true
+	if row := ansi.Strip(m.renderMetricsRow(history, 120)); !strings.Contains(row, "last 2m30s") {
+		t.Errorf("metrics row = %q, want the 2m30s window", row)
+	}
+}
@@ -1,37 +1,268 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log"
+	"net"
 	"os"
+	"os/signal"
+	"runtime/debug"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/sumant1122/perfdeck/internal/config"
+	"github.com/sumant1122/perfdeck/internal/export"
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/internal/report"
 	"github.com/sumant1122/perfdeck/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-const version = "0.4.2"
+// version is a var so release builds can override it with -ldflags -X.
+var version = "0.4.2"
+
+type options struct {
+	showVersion  bool
+	doctor       bool
+	configPath   bool
+	debugPath    string
+	reportPath   string
+	demo         bool
+	statsdAddr   string
+	serveAddr    string
+	lowBandwidth bool
+	noAltScreen  bool
+	disable      string
+	cmd          []string
+}
 
 func main() {
-	if printVersion() {
-		return
+	os.Exit(run())
+}
+
+// run is the body of main, returning the exit code so that its deferred
+// cleanups (the debug log, the statsd client, the signal handler) run
+// before the process exits.
+func run() int {
+	opts := parseFlags()
+	if opts.showVersion {
+		fmt.Print(versionString())
+		return 0
+	}
+	if opts.doctor {
+		return monitor.Doctor(os.Stdout)
+	}
+	if opts.configPath {
+		if path, ok := config.ResolvedPath(); ok {
+			fmt.Println(path)
+		} else {
+			fmt.Println("none found, using defaults")
+		}
+		return 0
+	}
+
+	closeLog, err := setupLogging(opts.debugPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeLog()
+
+	monitor.Demo = opts.demo
+	loadOpts := config.LoadOptions{AdHocCmd: opts.cmd, DisableDefaults: splitList(opts.disable)}
+
+	if opts.reportPath != "" {
+		if err := writeReport(opts.reportPath, loadOpts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	// Cancelling ctx on SIGINT/SIGTERM kills any commands still running so
+	// no vmstat/mpstat children outlive the program.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if opts.serveAddr != "" {
+		if err := serve(ctx, opts.serveAddr, loadOpts); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return 0
+	}
+
+	m := ui.NewModelWithOptions(loadOpts).WithContext(ctx)
+	if opts.lowBandwidth {
+		m = m.WithLowBandwidth()
 	}
-	m := ui.NewModel()
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	if opts.statsdAddr != "" {
+		statsd, err := export.NewStatsdClient(opts.statsdAddr, "perfdeck")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer statsd.Close()
+		m = m.WithSampleHook(func(s monitor.MetricsSample) {
+			if err := statsd.Sample(s); err != nil {
+				log.Printf("%v", err)
+			}
+		})
+	}
+	p := tea.NewProgram(m, programOptions(ctx, opts)...)
 	if _, err := p.Run(); err != nil {
+		if errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil {
+			log.Printf("shutting down: %v", ctx.Err())
+			return 0
+		}
+		log.Printf("program error: %v", err)
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		return 1
+	}
+	return 0
+}
+
+// versionString renders the version line followed by whatever build
+// metadata the Go toolchain embedded in the binary.
+func versionString() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "perfdeck %s\n", version)
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b.String()
+	}
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			fmt.Fprintf(&b, "commit: %s\n", s.Value)
+		case "vcs.time":
+			fmt.Fprintf(&b, "built: %s\n", s.Value)
+		case "vcs.modified":
+			if s.Value == "true" {
+				b.WriteString("modified: true\n")
+			}
+		}
 	}
+	fmt.Fprintf(&b, "go: %s\n", info.GoVersion)
+	return b.String()
 }
 
-func printVersion() bool {
-	var showVersion bool
-	flag.BoolVar(&showVersion, "version", false, "print version and exit")
-	flag.BoolVar(&showVersion, "v", false, "print version and exit")
-	flag.Parse()
-	if showVersion {
-		fmt.Printf("perfdeck %s\n", version)
-		return true
+func parseFlags() options {
+	// The default command line exits on bad flags itself; what is left are
+	// flag values that don't parse.
+	opts, err := parseArgs(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	return opts
+}
+
+// parseArgs registers perfdeck's flags on fs and parses args with it.
+func parseArgs(fs *flag.FlagSet, args []string) (options, error) {
+	var opts options
+	fs.BoolVar(&opts.showVersion, "version", false, "print version and exit")
+	fs.BoolVar(&opts.showVersion, "v", false, "print version and exit")
+	fs.BoolVar(&opts.doctor, "doctor", false, "check which metric sources are available and exit")
+	fs.BoolVar(&opts.configPath, "config-path", false, "print the config file that would be loaded and exit")
+	fs.StringVar(&opts.debugPath, "debug", "", "write debug logs to `file`")
+	fs.StringVar(&opts.reportPath, "report", "", "run every tab once, write a markdown report to `file` and exit")
+	fs.StringVar(&opts.statsdAddr, "statsd", "", "send metric gauges to the statsd server at `host:port`")
+	fs.StringVar(&opts.serveAddr, "serve", "", "run without the TUI, serving /metrics and /healthz on `host:port`")
+	fs.BoolVar(&opts.lowBandwidth, "low-bandwidth", false, "refresh less often and skip animations and colors for slow links such as SSH")
+	fs.BoolVar(&opts.noAltScreen, "no-altscreen", false, "draw inline instead of on the alternate screen, keeping terminal scrollback")
+	fs.StringVar(&opts.disable, "disable", "", "comma-separated default tabs to drop, e.g. `uptime,iostat`")
+	cmdLine := fs.String("cmd", "", "show a single tab running `command` instead of the configured tabs")
+	fs.BoolVar(&opts.demo, "demo", os.Getenv("PERFDECK_FAKE") == "1", "show synthetic metrics instead of probing the system")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	// "--debug=~/perfdeck.log" reaches us with the tilde unexpanded.
+	opts.debugPath = config.ExpandHome(opts.debugPath)
+	opts.reportPath = config.ExpandHome(opts.reportPath)
+	if *cmdLine != "" {
+		cmd, err := config.SplitCommand(*cmdLine)
+		if err != nil {
+			return opts, fmt.Errorf("--cmd: %w", err)
+		}
+		opts.cmd = cmd
+	}
+	return opts, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// programOptions builds the Bubble Tea options for opts: the alternate
+// screen unless --no-altscreen asked to draw inline, and ctx for shutdown.
+func programOptions(ctx context.Context, opts options) []tea.ProgramOption {
+	progOpts := []tea.ProgramOption{tea.WithContext(ctx)}
+	if !opts.noAltScreen {
+		progOpts = append(progOpts, tea.WithAltScreen())
+	}
+	return progOpts
+}
+
+// serve runs headless: it samples the metrics every
+// global_refresh_interval and serves them for Prometheus at /metrics, with
+// a health check at /healthz, until ctx is cancelled.
+func serve(ctx context.Context, addr string, loadOpts config.LoadOptions) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("serve: %w", err)
+	}
+	cfg, _ := config.Load(loadOpts)
+	var latest export.Latest
+	go func() {
+		ticker := time.NewTicker(cfg.GlobalRefreshInterval.Duration)
+		defer ticker.Stop()
+		for {
+			latest.Set(monitor.SampleMetrics(ctx))
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	fmt.Fprintf(os.Stderr, "perfdeck: serving /metrics and /healthz on %s\n", ln.Addr())
+	return export.Serve(ctx, ln, export.NewMux(latest.Get, monitor.AvailableMetrics))
+}
+
+// writeReport snapshots every configured tab into a markdown file.
+func writeReport(path string, loadOpts config.LoadOptions) error {
+	_, tabs := config.Load(loadOpts)
+	if err := os.WriteFile(path, []byte(report.BuildReport(tabs, ui.ExecRunner)), 0o644); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
+
+// setupLogging routes the standard logger to path, or discards it when
+// path is empty so nothing leaks onto the TUI.
+func setupLogging(path string) (func(), error) {
+	if path == "" {
+		log.SetOutput(io.Discard)
+		return func() {}, nil
+	}
+	f, err := tea.LogToFile(path, "perfdeck")
+	if err != nil {
+		return nil, fmt.Errorf("open debug log: %w", err)
 	}
-	return false
+	log.Printf("perfdeck %s starting", version)
+	return func() { f.Close() }, nil
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestVersionString(t *testing.T) {
+	out := versionString()
+	first, _, _ := strings.Cut(out, "\n")
+	if first != "perfdeck "+version {
+		t.Errorf("first line = %q, want %q", first, "perfdeck "+version)
+	}
+	if !strings.Contains(out, "go: ") {
+		t.Errorf("expected Go version in output, got %q", out)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want func(options) bool
+	}{
+		{nil, func(o options) bool { return !o.noAltScreen && !o.lowBandwidth }},
+		{[]string{"--no-altscreen"}, func(o options) bool { return o.noAltScreen }},
+		{[]string{"--disable", "uptime, iostat,"}, func(o options) bool {
+			got := splitList(o.disable)
+			return len(got) == 2 && got[0] == "uptime" && got[1] == "iostat"
+		}},
+		{[]string{"--cmd", `watch-this --flag "two words"`}, func(o options) bool {
+			return slices.Equal(o.cmd, []string{"watch-this", "--flag", "two words"})
+		}},
+		{[]string{"--low-bandwidth", "--statsd", "localhost:8125"}, func(o options) bool {
+			return o.lowBandwidth && o.statsdAddr == "localhost:8125"
+		}},
+		{[]string{"--serve", ":9100"}, func(o options) bool {
+			return o.serveAddr == ":9100"
+		}},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("perfdeck", flag.ContinueOnError)
+		opts, err := parseArgs(fs, tt.args)
+		if err != nil {
+			t.Errorf("parseArgs(%q): %v", tt.args, err)
+			continue
+		}
+		if !tt.want(opts) {
+			t.Errorf("parseArgs(%q) = %+v", tt.args, opts)
+		}
+	}
+
+	for _, bad := range [][]string{{"--no-such-flag"}, {"--cmd", "echo 'unterminated"}} {
+		fs := flag.NewFlagSet("perfdeck", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		if _, err := parseArgs(fs, bad); err == nil {
+			t.Errorf("parseArgs(%q) should fail", bad)
+		}
+	}
+}
+
+func TestProgramOptionsAltScreen(t *testing.T) {
+	ctx := context.Background()
+	alt := programOptions(ctx, options{})
+	inline := programOptions(ctx, options{noAltScreen: true})
+	if len(alt) != len(inline)+1 {
+		t.Errorf("got %d options with the alt screen and %d inline, want one fewer inline", len(alt), len(inline))
+	}
+}
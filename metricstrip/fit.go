@@ -0,0 +1,69 @@
+package metricstrip
+
+import "strings"
+
+// Metric block names, in display order. These are also the values
+// accepted in Strip.Priority.
+var metricBlockNames = []string{"cpu", "mem", "load", "net"}
+
+// metricRanks maps each block name to its priority rank, lower being more
+// important. Names missing from priority rank after the listed ones, in
+// display order.
+func metricRanks(priority []string) map[string]int {
+	ranks := make(map[string]int, len(metricBlockNames))
+	for _, name := range priority {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, seen := ranks[name]; !seen {
+			ranks[name] = len(ranks)
+		}
+	}
+	for _, name := range metricBlockNames {
+		if _, seen := ranks[name]; !seen {
+			ranks[name] = len(ranks)
+		}
+	}
+	return ranks
+}
+
+// fitBlocks decides which blocks fit on one row of the given width. Blocks
+// are joined by sepWidth columns; when any is dropped a marker of
+// markerWidth columns (plus a separator) is appended. The lowest priority
+// block, the one with the highest rank, is dropped first.
+func fitBlocks(widths, ranks []int, sepWidth, markerWidth, width int) (keep []bool, dropped bool) {
+	keep = make([]bool, len(widths))
+	for i := range keep {
+		keep[i] = true
+	}
+	rowWidth := func() int {
+		parts, total := 0, 0
+		for i, w := range widths {
+			if keep[i] {
+				parts++
+				total += w
+			}
+		}
+		if dropped {
+			parts++
+			total += markerWidth
+		}
+		if parts > 1 {
+			total += sepWidth * (parts - 1)
+		}
+		return total
+	}
+
+	for rowWidth() > width {
+		drop := -1
+		for i := range widths {
+			if keep[i] && (drop == -1 || ranks[i] > ranks[drop]) {
+				drop = i
+			}
+		}
+		if drop == -1 {
+			break
+		}
+		keep[drop] = false
+		dropped = true
+	}
+	return keep, dropped
+}
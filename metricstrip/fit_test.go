@@ -0,0 +1,73 @@
+package metricstrip
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMetricRanks(t *testing.T) {
+	got := metricRanks([]string{"NET", "cpu", "net"})
+	want := map[string]int{"net": 0, "cpu": 1, "mem": 2, "load": 3}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("metricRanks = %v, want %v", got, want)
+	}
+}
+
+func TestFitBlocks(t *testing.T) {
+	tests := []struct {
+		name        string
+		widths      []int
+		ranks       []int
+		width       int
+		wantKeep    []bool
+		wantDropped bool
+	}{
+		{
+			name:     "fits",
+			widths:   []int{10, 10, 10},
+			ranks:    []int{0, 1, 2},
+			width:    36,
+			wantKeep: []bool{true, true, true},
+		},
+		{
+			name:        "drops lowest priority",
+			widths:      []int{10, 10, 10},
+			ranks:       []int{0, 1, 2},
+			width:       35,
+			wantKeep:    []bool{true, true, false},
+			wantDropped: true,
+		},
+		{
+			name:        "priority differs from display order",
+			widths:      []int{10, 10, 10},
+			ranks:       []int{2, 0, 1},
+			width:       30,
+			wantKeep:    []bool{false, true, true},
+			wantDropped: true,
+		},
+		{
+			name:        "marker must fit too",
+			widths:      []int{10, 10, 10},
+			ranks:       []int{0, 1, 2},
+			width:       24,
+			wantKeep:    []bool{true, false, false},
+			wantDropped: true,
+		},
+		{
+			name:        "nothing fits",
+			widths:      []int{10},
+			ranks:       []int{0},
+			width:       5,
+			wantKeep:    []bool{false},
+			wantDropped: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keep, dropped := fitBlocks(tt.widths, tt.ranks, 3, 1, tt.width)
+			if !reflect.DeepEqual(keep, tt.wantKeep) || dropped != tt.wantDropped {
+				t.Errorf("fitBlocks = %v, %t; want %v, %t", keep, dropped, tt.wantKeep, tt.wantDropped)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+// Package metricstrip renders perfdeck's metrics row, the CPU, MEM, LOAD
+// and NET sparklines with their current values, so other Bubble Tea
+// programs can embed it in their own views.
+package metricstrip
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/internal/spark"
+	"github.com/sumant1122/perfdeck/internal/theme"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// History is the recent values of each metric, oldest first.
+type History = monitor.MetricHistory
+
+// Sample is one raw metrics reading.
+type Sample = monitor.MetricsSample
+
+// Styles is a color theme for the row.
+type Styles = theme.Styles
+
+// DefaultStyles returns perfdeck's default theme.
+func DefaultStyles() Styles {
+	return theme.BuildStyles(0)
+}
+
+// DefaultSeparator joins the blocks of the row unless Separator is set.
+const DefaultSeparator = "   "
+
+// Series is an extra metric plotted after NET, auto-scaled to its
+// largest value.
+type Series struct {
+	Name   string
+	Values []float64
+}
+
+// Strip is everything the metrics row is drawn from. Only Styles is
+// needed; the zero value of every other field is the default.
+type Strip struct {
+	Styles Styles
+	// FormatRate renders the NET value; nil means binary bytes, e.g.
+	// "1.5MiB/s".
+	FormatRate func(float64) string
+	// Sample is the latest raw sample, for the CPU iowait/steal detail
+	// and MemDetail.
+	Sample Sample
+	// MemDetail shows used/total memory next to the MEM percentage.
+	MemDetail bool
+	// ScaleLabel names the top of the auto-scaled LOAD and NET
+	// sparklines.
+	ScaleLabel bool
+	// PeakHold marks each sparkline's highest value.
+	PeakHold bool
+	// Window, when positive, adds a label for the time the sparklines
+	// span, e.g. "last 2m30s".
+	Window time.Duration
+	// Priority lists block names ("cpu", "mem", "load", "net") to keep
+	// first when the row is too narrow.
+	Priority []string
+	// Separator joins the blocks; empty means DefaultSeparator.
+	Separator string
+	// Extra are plotted after NET.
+	Extra []Series
+}
+
+// Render draws the CPU, MEM, LOAD and NET sparklines for history in a row
+// width cells wide, with the default settings.
+func Render(history History, width int, styles Styles) string {
+	return Strip{Styles: styles}.Render(history, width)
+}
+
+// Render draws the row of sparklines and current values.
+func (s Strip) Render(history History, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if len(s.Styles.Ramp) == 0 {
+		s.Styles.Ramp = spark.ASCII
+	}
+	if s.FormatRate == nil {
+		s.FormatRate = monitor.FormatRate
+	}
+
+	// Helper to render a single metric block with color
+	renderBlock := func(label string, valStr string, data []float64, min, max float64, isPercent bool) string {
+		// Determine color based on latest value
+		value := s.Styles.Processing.Render(valStr)
+		if last, ok := monitor.Latest(data); ok {
+			// Normalize value for color mapping
+			param := last
+			if !isPercent {
+				// efficient approximation for load/net: reasonable max
+				// load: max 4.0 (green), 8.0 (yellow), >8.0 (red)
+				// net: max 1MB/s (green), 10MB/s (yellow), >10MB/s (red)
+				// This is heuristic, percent is easier
+				if max > 0 {
+					param = (last / max) * 100
+				}
+			}
+
+			value = s.renderSeverity(valStr, percentSeverity(param))
+		}
+
+		sl := s.renderSparkline(data, min, max)
+		// Colorize the value; the sparkline carries its own gradient
+		return fmt.Sprintf("%s %s %s", label, value, sl)
+	}
+
+	var blocks, names []string
+
+	// CPU
+	if len(history.CPU) > 0 {
+		val, _ := monitor.Latest(history.CPU)
+		valStr := fmt.Sprintf("%0.0f%%", val)
+		if detail := cpuDetail(s.Sample); detail != "" {
+			valStr += " " + detail
+		}
+		names = append(names, "cpu")
+		blocks = append(blocks, renderBlock("CPU", valStr, history.CPU, 0, 100, true))
+	}
+
+	// MEM
+	if len(history.Mem) > 0 {
+		val, _ := monitor.Latest(history.Mem)
+		valStr := fmt.Sprintf("%0.0f%%", val)
+		if s.MemDetail && s.Sample.MemTotalMB > 0 {
+			valStr += fmt.Sprintf(" (%s/%sG)", monitor.FormatGB(s.Sample.MemUsedMB), monitor.FormatGB(s.Sample.MemTotalMB))
+		}
+		names = append(names, "mem")
+		blocks = append(blocks, renderBlock("MEM", valStr, history.Mem, 0, 100, true))
+	}
+
+	// LOAD (heuristic color: <1.0 green, <high yellow, >high red)
+	if len(history.Load) > 0 {
+		val, _ := monitor.Latest(history.Load)
+		max := monitor.Max(history.Load)
+		if max < 2.0 {
+			max = 2.0
+		} // Minimum scale for load
+
+		value := s.renderSeverity(fmt.Sprintf("%0.2f", val), loadSeverity(val))
+		sl := s.renderSparkline(history.Load, 0, max)
+		names = append(names, "load")
+		blocks = append(blocks, fmt.Sprintf("LOAD %s %s", value, sl)+s.scaleLabel(fmt.Sprintf("%0.2f", max)))
+	}
+
+	// NET
+	if len(history.Net) > 0 {
+		val, _ := monitor.Latest(history.Net)
+		max := monitor.Max(history.Net)
+		if max < 1 {
+			max = 1
+		}
+		names = append(names, "net")
+		blocks = append(blocks, renderBlock("NET", s.FormatRate(val), history.Net, 0, max, false)+s.scaleLabel(s.FormatRate(max)))
+	}
+
+	// Extra series, auto-scaled like LOAD and NET
+	for _, e := range s.Extra {
+		if len(e.Values) == 0 {
+			continue
+		}
+		max := monitor.Max(e.Values)
+		if max < 1 {
+			max = 1
+		}
+		names = append(names, strings.ToLower(e.Name))
+		blocks = append(blocks, renderBlock(strings.ToUpper(e.Name), fmt.Sprintf("%0.1f", e.Values[len(e.Values)-1]), e.Values, 0, max, false))
+	}
+
+	if len(blocks) == 0 {
+		return s.Styles.Summary.Width(width).Render("Waiting for metrics...")
+	}
+	if s.Window > 0 {
+		names = append(names, "window")
+		blocks = append(blocks, lipgloss.NewStyle().Faint(true).Background(s.Styles.AccentDark).Render(formatWindow(s.Window)))
+	}
+
+	row := s.fitMetricBlocks(blocks, names, width-s.Styles.Summary.GetHorizontalFrameSize())
+	return s.Styles.Summary.Width(width).Render(row)
+}
+
+// scaleLabel returns the " (max X)" suffix naming the top of an
+// auto-scaled sparkline, or "" unless ScaleLabel is on.
+func (s Strip) scaleLabel(max string) string {
+	if !s.ScaleLabel {
+		return ""
+	}
+	return fmt.Sprintf(" (max %s)", max)
+}
+
+// cpuDetailMin is the iowait or steal percentage below which the CPU block
+// leaves it out.
+const cpuDetailMin = 1.0
+
+// cpuDetail annotates the CPU value with iowait and steal, e.g.
+// "io 5% st 2%", omitting either when it is negligible or unknown.
+func cpuDetail(s Sample) string {
+	if !s.OkCPUDetail {
+		return ""
+	}
+	var parts []string
+	if s.IOWait >= cpuDetailMin {
+		parts = append(parts, fmt.Sprintf("io %0.0f%%", s.IOWait))
+	}
+	if s.Steal >= cpuDetailMin {
+		parts = append(parts, fmt.Sprintf("st %0.0f%%", s.Steal))
+	}
+	return strings.Join(parts, " ")
+}
+
+// fitMetricBlocks joins the rendered blocks, dropping the lowest priority
+// ones until the row fits width and marking the cut with an ellipsis.
+func (s Strip) fitMetricBlocks(blocks, names []string, width int) string {
+	const marker = "…"
+	sep := s.Separator
+	if sep == "" {
+		sep = DefaultSeparator
+	}
+	sep = lipgloss.NewStyle().Foreground(s.Styles.Muted).Background(s.Styles.AccentDark).Render(sep)
+	ranks := metricRanks(s.Priority)
+	widths := make([]int, len(blocks))
+	blockRanks := make([]int, len(blocks))
+	for i, b := range blocks {
+		widths[i] = lipgloss.Width(b)
+		rank, ok := ranks[names[i]]
+		if !ok {
+			// Extra series and the window label, unless named in
+			// Priority, go first.
+			rank = len(ranks)
+		}
+		blockRanks[i] = rank
+	}
+	keep, dropped := fitBlocks(widths, blockRanks, lipgloss.Width(sep), lipgloss.Width(marker), width)
+	var kept []string
+	for i, b := range blocks {
+		if keep[i] {
+			kept = append(kept, b)
+		}
+	}
+	if dropped {
+		kept = append(kept, marker)
+	}
+	return strings.Join(kept, sep)
+}
@@ -0,0 +1,79 @@
+package metricstrip
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/internal/spark"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRender(t *testing.T) {
+	history := History{
+		CPU:  []float64{10, 40, 90},
+		Mem:  []float64{55, 60},
+		Load: []float64{0.5, 3.25},
+		Net:  []float64{100, 2048},
+	}
+
+	row := ansi.Strip(Render(history, 200, DefaultStyles()))
+	for _, block := range []string{"CPU 90%", "MEM 60%", "LOAD 3.25", "NET 2.0MiB/s"} {
+		if !strings.Contains(row, block) {
+			t.Errorf("row %q missing %q", row, block)
+		}
+	}
+	if strings.Contains(row, "(max") || strings.Contains(row, "last ") {
+		t.Errorf("default row has optional labels: %q", row)
+	}
+
+	s := Strip{
+		Styles:     DefaultStyles(),
+		FormatRate: monitor.FormatRateBits,
+		ScaleLabel: true,
+		Window:     150 * time.Second,
+		Extra:      []Series{{Name: "avg", Values: []float64{12.5}}},
+	}
+	row = ansi.Strip(s.Render(history, 300))
+	for _, want := range []string{"(max 3.25)", "NET 16.8Mbps", "(max 16.8Mbps)", "AVG 12.5", "last 2m30s"} {
+		if !strings.Contains(row, want) {
+			t.Errorf("row %q missing %q", row, want)
+		}
+	}
+
+	if got := Render(History{}, 80, DefaultStyles()); !strings.Contains(got, "Waiting for metrics") {
+		t.Errorf("empty history = %q, want a waiting message", got)
+	}
+	if got := Render(history, 0, DefaultStyles()); got != "" {
+		t.Errorf("zero width = %q, want nothing", got)
+	}
+}
+
+func TestRenderWithoutRamp(t *testing.T) {
+	// Hand-built styles leave Ramp nil; the sparklines still draw.
+	history := History{CPU: []float64{0, 50, 100}}
+	row := ansi.Strip(Render(history, 80, Styles{}))
+	if want := spark.Render(history.CPU, 0, 100, spark.ASCII); !strings.Contains(row, "CPU 100% "+want) {
+		t.Errorf("row %q missing the ASCII sparkline %q", row, want)
+	}
+}
+
+func TestCPUDetail(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample Sample
+		want   string
+	}{
+		{"unknown", Sample{IOWait: 5, Steal: 2}, ""},
+		{"negligible", Sample{IOWait: 0.4, Steal: 0.2, OkCPUDetail: true}, ""},
+		{"both", Sample{IOWait: 5, Steal: 2, OkCPUDetail: true}, "io 5% st 2%"},
+		{"steal only", Sample{Steal: 12, OkCPUDetail: true}, "st 12%"},
+	}
+	for _, tt := range tests {
+		if got := cpuDetail(tt.sample); got != tt.want {
+			t.Errorf("%s: cpuDetail = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package metricstrip
+
+import "github.com/charmbracelet/lipgloss"
+
+// severity is how alarming a metric value is, from fine to critical.
+type severity int
+
+const (
+	severityOK severity = iota
+	severityWarn
+	severityCrit
+)
+
+// percentSeverity grades a 0-100 value: below 50 is fine, below 80 a
+// warning, anything higher critical.
+func percentSeverity(p float64) severity {
+	switch {
+	case p < 50:
+		return severityOK
+	case p < 80:
+		return severityWarn
+	default:
+		return severityCrit
+	}
+}
+
+// PercentStyle is the color styles gives a 0-100 value, graded the same
+// way as the row's CPU and MEM values.
+func PercentStyle(p float64, styles Styles) lipgloss.Style {
+	return Strip{Styles: styles}.severityStyle(percentSeverity(p))
+}
+
+// loadSeverity grades a load average: below 1 is fine, below 4 a warning.
+func loadSeverity(load float64) severity {
+	switch {
+	case load < 1.0:
+		return severityOK
+	case load < 4.0:
+		return severityWarn
+	default:
+		return severityCrit
+	}
+}
+
+// severityGlyph is the text marker for s, for themes where the colors
+// cannot tell severities apart.
+func severityGlyph(s severity) string {
+	switch s {
+	case severityWarn:
+		return "!"
+	case severityCrit:
+		return "!!"
+	default:
+		return "ok"
+	}
+}
+
+// severityStyle is the color for level in the current theme.
+func (s Strip) severityStyle(level severity) lipgloss.Style {
+	switch level {
+	case severityWarn:
+		return s.Styles.Yellow
+	case severityCrit:
+		return s.Styles.Red
+	default:
+		return s.Styles.Green
+	}
+}
+
+// renderSeverity colors a metric value by level and, on mono themes,
+// appends the severity glyph so it does not rely on color alone.
+func (s Strip) renderSeverity(value string, level severity) string {
+	if s.Styles.Mono {
+		value += " " + severityGlyph(level)
+	}
+	return s.severityStyle(level).Render(value)
+}
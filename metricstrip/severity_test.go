@@ -0,0 +1,79 @@
+package metricstrip
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/theme"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestSeverityGlyph(t *testing.T) {
+	tests := []struct {
+		percent float64
+		load    float64
+		want    string
+	}{
+		{0, 0, "ok"},
+		{49.9, 0.99, "ok"},
+		{50, 1.0, "!"},
+		{79.9, 3.99, "!"},
+		{80, 4.0, "!!"},
+		{100, 12, "!!"},
+	}
+	for _, tt := range tests {
+		if got := severityGlyph(percentSeverity(tt.percent)); got != tt.want {
+			t.Errorf("percent %v: glyph = %q, want %q", tt.percent, got, tt.want)
+		}
+		if got := severityGlyph(loadSeverity(tt.load)); got != tt.want {
+			t.Errorf("load %v: glyph = %q, want %q", tt.load, got, tt.want)
+		}
+	}
+}
+
+func TestMonoThemeGlyphs(t *testing.T) {
+	history := History{
+		CPU:  []float64{95},
+		Mem:  []float64{60},
+		Load: []float64{0.5},
+	}
+
+	if row := ansi.Strip(Render(history, 200, DefaultStyles())); strings.Contains(row, "!!") {
+		t.Errorf("color theme should not add glyphs: %q", row)
+	}
+
+	mono := -1
+	for i, th := range theme.Themes {
+		if th.Mono {
+			mono = i
+		}
+	}
+	if mono < 0 {
+		t.Fatal("no mono theme built in")
+	}
+	row := ansi.Strip(Render(history, 200, theme.BuildStyles(mono)))
+	for _, want := range []string{"CPU 95% !!", "MEM 60% !", "LOAD 0.50 ok"} {
+		if !strings.Contains(row, want) {
+			t.Errorf("mono row missing %q: %q", want, row)
+		}
+	}
+}
+
+func TestPercentStyle(t *testing.T) {
+	styles := DefaultStyles()
+	tests := []struct {
+		percent float64
+		want    lipgloss.Style
+	}{
+		{10, styles.Green},
+		{50, styles.Yellow},
+		{80, styles.Red},
+	}
+	for _, tt := range tests {
+		if got, want := PercentStyle(tt.percent, styles).Render("x"), tt.want.Render("x"); got != want {
+			t.Errorf("PercentStyle(%v) renders %q, want %q", tt.percent, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package metricstrip
+
+import (
+	"strings"
+
+	"github.com/sumant1122/perfdeck/internal/monitor"
+	"github.com/sumant1122/perfdeck/internal/spark"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// peakIndex is the position of the highest value, the most recent one on
+// ties, or -1 when values holds only gaps.
+func peakIndex(values []float64) int {
+	peak := -1
+	for i, v := range values {
+		if !monitor.IsGap(v) && (peak == -1 || v >= values[peak]) {
+			peak = i
+		}
+	}
+	return peak
+}
+
+// renderSparkline draws a metrics row sparkline as a green to red
+// gradient, with the peak in the red style on top when PeakHold is on.
+func (s Strip) renderSparkline(values []float64, min, max float64) string {
+	peak := -1
+	if s.PeakHold {
+		peak = peakIndex(values)
+	}
+	return sparklineGradient(values, min, max, s.Styles, peak, s.Styles.Red)
+}
+
+// sparklineGradient renders values with each rune colored by its own level
+// between min and max, graded like a percentage by percentSeverity, so
+// spikes stand out along the line. The rune at peak, unless it is -1, gets
+// peakStyle laid over its level's style. Runs of one color share a style.
+func sparklineGradient(values []float64, min, max float64, s Styles, peak int, peakStyle lipgloss.Style) string {
+	runes := []rune(spark.Render(values, min, max, s.Ramp))
+	if len(runes) == 0 {
+		return ""
+	}
+	if max <= min {
+		max = min + 1
+	}
+	styles := []lipgloss.Style{lipgloss.NewStyle().Foreground(s.Muted), s.Green, s.Yellow, s.Red}
+	// Band 0 is for gaps; the rest follow the severities from green to red.
+	band := func(v float64) int {
+		if monitor.IsGap(v) {
+			return 0
+		}
+		return 1 + int(percentSeverity((v-min)/(max-min)*100))
+	}
+	// The peak is a run of its own, after the bands.
+	key := func(i int) int {
+		if i == peak {
+			return len(styles)
+		}
+		return band(values[i])
+	}
+	style := func(i int) lipgloss.Style {
+		if i == peak {
+			return peakStyle.Inherit(styles[band(values[i])])
+		}
+		return styles[band(values[i])]
+	}
+	var b strings.Builder
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) && key(i) == key(start) {
+			continue
+		}
+		b.WriteString(style(start).Render(string(runes[start:i])))
+		start = i
+	}
+	return b.String()
+}
@@ -0,0 +1,57 @@
+package metricstrip
+
+import (
+	"math"
+	"testing"
+
+	"github.com/sumant1122/perfdeck/internal/spark"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestPeakIndex(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   int
+	}{
+		{"empty", nil, -1},
+		{"middle", []float64{0, 100, 50}, 1},
+		{"latest tie", []float64{100, 0, 100}, 2},
+		{"single", []float64{50}, 0},
+		{"gaps only", []float64{math.NaN(), math.NaN()}, -1},
+	}
+	for _, tt := range tests {
+		if got := peakIndex(tt.values); got != tt.want {
+			t.Errorf("%s: peakIndex(%v) = %d, want %d", tt.name, tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestSparklineGradient(t *testing.T) {
+	tag := func(name string) lipgloss.Style {
+		return lipgloss.NewStyle().Transform(func(s string) string { return "<" + name + ">" + s })
+	}
+	s := Styles{Green: tag("g"), Yellow: tag("y"), Red: tag("r"), Ramp: spark.ASCII}
+	mark := lipgloss.NewStyle().Transform(func(s string) string { return "[" + s + "]" })
+
+	tests := []struct {
+		name   string
+		values []float64
+		peak   int
+		want   string
+	}{
+		{"empty", nil, -1, ""},
+		{"bands", []float64{0, 60, 100}, -1, "<g> <y>+<r>@"},
+		{"runs share a style", []float64{10, 20, 90, 95}, -1, "<g> .<r>%%"},
+		{"peak keeps the gradient", []float64{10, 20, 90, 95}, 3, "<g> .<r>%[%]"},
+		{"peak splits a run", []float64{10, 20, 30}, 1, "<g> [.]<g>:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparklineGradient(tt.values, 0, 100, s, tt.peak, mark); got != tt.want {
+				t.Errorf("sparklineGradient(%v, peak %d) = %q, want %q", tt.values, tt.peak, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package metricstrip
+
+import (
+	"strings"
+	"time"
+)
+
+// formatWindow renders a window as a label, e.g. "last 2m30s", leaving
+// out zero minutes and seconds ("last 5m", "last 1h").
+func formatWindow(d time.Duration) string {
+	s := d.Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return "last " + s
+}
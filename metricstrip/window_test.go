@@ -0,0 +1,24 @@
+package metricstrip
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatWindow(t *testing.T) {
+	tests := []struct {
+		window time.Duration
+		want   string
+	}{
+		{150 * time.Second, "last 2m30s"},
+		{time.Minute, "last 1m"},
+		{5 * time.Minute, "last 5m"},
+		{time.Hour, "last 1h"},
+		{15 * time.Second, "last 15s"},
+	}
+	for _, tt := range tests {
+		if got := formatWindow(tt.window); got != tt.want {
+			t.Errorf("formatWindow(%s) = %q, want %q", tt.window, got, tt.want)
+		}
+	}
+}